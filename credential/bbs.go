@@ -0,0 +1,68 @@
+package credential
+
+import (
+	"fmt"
+
+	"github.com/workdaycredentials/ledger-common/proof"
+)
+
+// DeriveProof takes a VerifiableCredential signed with a BbsBlsSignature2020 proof and a list of
+// CredentialSubject fields the holder wishes to reveal, and produces a new VerifiableCredential
+// whose CredentialSubject contains only those fields, carrying a BbsSelectiveDisclosureProof proof
+// that lets a verifier recheck the original issuer signature over the full statement sequence
+// without ever seeing the plaintext, or even the dot-path, of the fields left out. This is not a
+// zero-knowledge proof (see proof.BbsDerivedProofData): the derived proof carries the issuer's
+// original signature and full per-statement digest manifest unchanged, so presentations derived
+// from the same credential remain correlatable with each other, and a verifier who already knows
+// (or can guess) a hidden field's dot-path can still dictionary-attack a low-entropy value's
+// unsalted digest. Metadata fields (issuer, id, etc.) are always carried over unredacted.
+func DeriveProof(cred *VerifiableCredential, reveal []string) (*VerifiableCredential, error) {
+	if cred.Proof == nil || cred.Proof.Type != proof.BbsBlsSignature2020 {
+		return nil, fmt.Errorf("credential is not signed with %s", proof.BbsBlsSignature2020)
+	}
+
+	revealed := make(map[string]interface{}, len(reveal))
+	for _, field := range reveal {
+		v, ok := cred.CredentialSubject[field]
+		if !ok {
+			return nil, fmt.Errorf("cannot reveal %q: not present in credential subject", field)
+		}
+		revealed[field] = v
+	}
+
+	derived := &VerifiableCredential{
+		UnsignedVerifiableCredential: UnsignedVerifiableCredential{
+			Metadata:          cred.Metadata,
+			CredentialSubject: revealed,
+		},
+	}
+
+	// The set of dot-paths that actually survive redaction (issuer/id/metadata plus whichever
+	// credentialSubject fields reveal asked for) is exactly what DeriveBbsProof should label in the
+	// manifest; anything else stays an opaque digest.
+	revealedPaths, err := proof.CanonicalStatementPaths(derived)
+	if err != nil {
+		return nil, err
+	}
+	revealedSet := make(map[string]bool, len(revealedPaths))
+	for _, p := range revealedPaths {
+		revealedSet[p] = true
+	}
+
+	derivedProof, err := proof.DeriveBbsProof(cred, cred.Proof, revealedSet)
+	if err != nil {
+		return nil, err
+	}
+	derived.Proof = derivedProof
+	return derived, nil
+}
+
+// VerifyDerivedProof checks a BbsSelectiveDisclosureProof derived proof: that every field the derived
+// credential still carries matches what the issuer actually signed, and that the issuer's BBS+
+// signature over the full original statement sequence is valid.
+func VerifyDerivedProof(cred *VerifiableCredential, verifier proof.Verifier) error {
+	if cred.Proof == nil || cred.Proof.Type != proof.BbsSelectiveDisclosureProof {
+		return fmt.Errorf("credential does not carry a %s proof", proof.BbsSelectiveDisclosureProof)
+	}
+	return proof.VerifyBbsDerivedProof(cred, cred.Proof, verifier)
+}
@@ -0,0 +1,219 @@
+package credential
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/workdaycredentials/ledger-common/proof"
+)
+
+// fakeBbsKeyPair is a deterministic, non-pairing stand-in for a real BLS12-381 BBS+ key pair (see
+// the identical helper in proof/bbs_test.go): it exists only to exercise DeriveProof/
+// VerifyDerivedProof's plumbing without a real pairing backend.
+type fakeBbsKeyPair struct {
+	secret string
+	keyID  string
+}
+
+func (k *fakeBbsKeyPair) KeyID() string { return k.keyID }
+
+// Sign and Verify treat a single message as a one-message sequence, so fakeBbsKeyPair also
+// satisfies the plain Signer/Verifier interfaces the suites take.
+func (k *fakeBbsKeyPair) Sign(message []byte) ([]byte, error) {
+	return k.SignMultiMessage([][]byte{message})
+}
+
+func (k *fakeBbsKeyPair) Verify(message, signature []byte) error {
+	return k.VerifyMultiMessage([][]byte{message}, signature)
+}
+
+func (k *fakeBbsKeyPair) SignMultiMessage(messages [][]byte) ([]byte, error) {
+	return fakeBbsDigest(k.secret, messages), nil
+}
+
+func (k *fakeBbsKeyPair) VerifyMultiMessage(messages [][]byte, signature []byte) error {
+	if !bytes.Equal(fakeBbsDigest(k.secret, messages), signature) {
+		return errInvalidFakeBbsSignature
+	}
+	return nil
+}
+
+var errInvalidFakeBbsSignature = fakeBbsError("fake BBS+ signature verification failed")
+
+type fakeBbsError string
+
+func (e fakeBbsError) Error() string { return string(e) }
+
+func fakeBbsDigest(secret string, messages [][]byte) []byte {
+	h, _ := blake2b.New384(nil)
+	h.Write([]byte(secret))
+	for _, m := range messages {
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(m)))
+		h.Write(length[:])
+		h.Write(m)
+	}
+	return h.Sum(nil)
+}
+
+func signBbsCredential(t *testing.T, cred *VerifiableCredential, keyPair *fakeBbsKeyPair) {
+	t.Helper()
+	suite, err := proof.SignatureSuites().GetSuiteForCredentials(proof.BbsBlsSignature2020, proof.V2)
+	if err != nil {
+		t.Fatalf("GetSuiteForCredentials returned error: %v", err)
+	}
+	if err := suite.Sign(cred, keyPair, &proof.ProofOptions{}); err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+}
+
+func TestDeriveProof_Succeeds(t *testing.T) {
+	cred := &VerifiableCredential{
+		UnsignedVerifiableCredential: UnsignedVerifiableCredential{
+			Metadata: Metadata{},
+			CredentialSubject: map[string]interface{}{
+				SubjectIDAttribute: "did:example:subject",
+				"name":             "Alice",
+				"age":              30,
+			},
+		},
+	}
+	keyPair := &fakeBbsKeyPair{secret: "issuer-secret", keyID: "did:example:issuer#key-1"}
+	signBbsCredential(t, cred, keyPair)
+
+	derived, err := DeriveProof(cred, []string{"name"})
+	if err != nil {
+		t.Fatalf("DeriveProof returned error: %v", err)
+	}
+	if _, ok := derived.CredentialSubject["name"]; !ok {
+		t.Errorf("expected the revealed field to survive into the derived credential")
+	}
+	if _, ok := derived.CredentialSubject["age"]; ok {
+		t.Errorf("expected a non-revealed field to be absent from the derived credential")
+	}
+	if derived.Proof == nil || derived.Proof.Type != proof.BbsSelectiveDisclosureProof {
+		t.Errorf("expected a %s derived proof, got %v", proof.BbsSelectiveDisclosureProof, derived.Proof)
+	}
+}
+
+func TestDeriveProof_DoesNotLeakHiddenFieldPaths(t *testing.T) {
+	cred := &VerifiableCredential{
+		UnsignedVerifiableCredential: UnsignedVerifiableCredential{
+			Metadata: Metadata{},
+			CredentialSubject: map[string]interface{}{
+				SubjectIDAttribute: "did:example:subject",
+				"name":             "Alice",
+				"age":              30,
+				"email":            "alice@example.com",
+			},
+		},
+	}
+	keyPair := &fakeBbsKeyPair{secret: "issuer-secret", keyID: "did:example:issuer#key-1"}
+	signBbsCredential(t, cred, keyPair)
+
+	derived, err := DeriveProof(cred, []string{"name"})
+	if err != nil {
+		t.Fatalf("DeriveProof returned error: %v", err)
+	}
+
+	encoded, err := base64.RawURLEncoding.DecodeString(derived.Proof.SignatureValue)
+	if err != nil {
+		t.Fatalf("failed to decode derived proof: %v", err)
+	}
+	var data proof.BbsDerivedProofData
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		t.Fatalf("failed to unmarshal derived proof data: %v", err)
+	}
+
+	for _, s := range data.Statements {
+		if s.Path == "credentialSubject.age" || s.Path == "credentialSubject.email" {
+			t.Errorf("derived proof manifest leaked the dot-path of a field the holder chose to hide: %+v", s)
+		}
+	}
+}
+
+func TestDeriveProof_RejectsUnknownField(t *testing.T) {
+	cred := &VerifiableCredential{
+		UnsignedVerifiableCredential: UnsignedVerifiableCredential{
+			CredentialSubject: map[string]interface{}{SubjectIDAttribute: "did:example:subject"},
+		},
+	}
+	cred.Proof = &proof.Proof{
+		Type:           proof.BbsBlsSignature2020,
+		SignatureValue: base64.RawURLEncoding.EncodeToString([]byte("fake-issuer-signature")),
+	}
+
+	if _, err := DeriveProof(cred, []string{"nonexistent"}); err == nil {
+		t.Fatalf("expected an error revealing a field absent from the credential subject")
+	}
+}
+
+func TestVerifyDerivedProof_AcceptsFaithfulDerivation(t *testing.T) {
+	cred := &VerifiableCredential{
+		UnsignedVerifiableCredential: UnsignedVerifiableCredential{
+			Metadata: Metadata{},
+			CredentialSubject: map[string]interface{}{
+				SubjectIDAttribute: "did:example:subject",
+				"name":             "Alice",
+				"age":              30,
+			},
+		},
+	}
+	keyPair := &fakeBbsKeyPair{secret: "issuer-secret", keyID: "did:example:issuer#key-1"}
+	signBbsCredential(t, cred, keyPair)
+
+	derived, err := DeriveProof(cred, []string{"name"})
+	if err != nil {
+		t.Fatalf("DeriveProof returned error: %v", err)
+	}
+
+	if err := VerifyDerivedProof(derived, keyPair); err != nil {
+		t.Errorf("VerifyDerivedProof rejected a faithfully derived credential: %v", err)
+	}
+}
+
+func TestVerifyDerivedProof_RejectsTamperedSubject(t *testing.T) {
+	cred := &VerifiableCredential{
+		UnsignedVerifiableCredential: UnsignedVerifiableCredential{
+			Metadata: Metadata{},
+			CredentialSubject: map[string]interface{}{
+				SubjectIDAttribute: "did:example:subject",
+				"name":             "Alice",
+			},
+		},
+	}
+	keyPair := &fakeBbsKeyPair{secret: "issuer-secret", keyID: "did:example:issuer#key-1"}
+	signBbsCredential(t, cred, keyPair)
+
+	derived, err := DeriveProof(cred, []string{"name"})
+	if err != nil {
+		t.Fatalf("DeriveProof returned error: %v", err)
+	}
+	derived.CredentialSubject["name"] = "Mallory"
+
+	if err := VerifyDerivedProof(derived, keyPair); err == nil {
+		t.Errorf("expected VerifyDerivedProof to reject a credential subject altered after derivation")
+	}
+}
+
+func TestVerifyDerivedProof_RequiresDerivedProofType(t *testing.T) {
+	cred := &VerifiableCredential{
+		UnsignedVerifiableCredential: UnsignedVerifiableCredential{
+			CredentialSubject: map[string]interface{}{SubjectIDAttribute: "did:example:subject"},
+		},
+	}
+	cred.Proof = &proof.Proof{
+		Type:           proof.BbsBlsSignature2020,
+		SignatureValue: base64.RawURLEncoding.EncodeToString([]byte("fake-issuer-signature")),
+	}
+
+	keyPair := &fakeBbsKeyPair{secret: "issuer-secret", keyID: "did:example:issuer#key-1"}
+	if err := VerifyDerivedProof(cred, keyPair); err == nil {
+		t.Fatalf("expected an error verifying a non-derived proof type as a derived proof")
+	}
+}
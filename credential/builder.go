@@ -1,6 +1,8 @@
 package credential
 
 import (
+	"encoding/json"
+
 	"gopkg.in/go-playground/validator.v9"
 
 	"github.com/workdaycredentials/ledger-common/proof"
@@ -20,6 +22,13 @@ type Builder struct {
 	Signer proof.Signer `validate:"required"`
 	// SignatureType specifies the suite used to generate the credential signature
 	SignatureType proof.SignatureType `validate:"required"`
+	// ResourceDigest, if set, is applied to both Data and Metadata before signing so that external
+	// resources referenced by URL (schemas, evidence documents, revocation lists) get a content
+	// digest that the signature covers, wherever in the credential they're referenced from. Leave
+	// unset to sign the claims as given. Build operates on copies: neither the caller's Data map nor
+	// the caller's *Metadata is mutated, so the same *Metadata can safely be reused across multiple
+	// Build calls (e.g. templated across a batch of subjects).
+	ResourceDigest *ResourceDigest
 }
 
 // Build returns a signed Verifiable Credential using the current state of the builder.
@@ -39,13 +48,57 @@ func (b Builder) Build() (*VerifiableCredential, error) {
 		credSubjects[k] = v
 	}
 
+	// metadata is a copy of *b.Metadata that ResourceDigest.Apply (below) can freely mutate: the
+	// caller's own Metadata must come out of Build untouched, the same way credSubjects is built
+	// fresh from b.Data rather than digesting b.Data in place.
+	metadata := *b.Metadata
+
+	if b.ResourceDigest != nil {
+		// credSubjects still shares its nested reference objects with b.Data, and Apply writes
+		// digestSRI onto them in place, so digest a deep copy instead.
+		subjectFields, err := structToMap(credSubjects)
+		if err != nil {
+			return nil, err
+		}
+		credSubjects = subjectFields
+		if _, err := b.ResourceDigest.Apply(credSubjects); err != nil {
+			return nil, err
+		}
+		// Schema/evidence references can live on Metadata just as well as CredentialSubject, so
+		// digest it too; Metadata's own resource references (if any) must be covered by the
+		// signature the same way CredentialSubject's are.
+		metadataFields, err := structToMap(metadata)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := b.ResourceDigest.Apply(metadataFields); err != nil {
+			return nil, err
+		}
+		if err := mapToStruct(metadataFields, &metadata); err != nil {
+			return nil, err
+		}
+	}
+
 	options := &proof.ProofOptions{ProofPurpose: proof.AssertionMethodPurpose}
+
+	// BBS+ signs the full statement sequence in one signature, so selective disclosure happens
+	// later via DeriveProof rather than by signing each claim into ClaimProofs up front.
+	if b.SignatureType == proof.BbsBlsSignature2020 {
+		cred := &VerifiableCredential{
+			UnsignedVerifiableCredential: UnsignedVerifiableCredential{
+				Metadata:          metadata,
+				CredentialSubject: credSubjects,
+			},
+		}
+		return cred, suite.Sign(cred, b.Signer, options)
+	}
+
 	// Compute the claim proofs for selective disclosure.
 	var claimProofs = make(map[string]proof.Proof, len(credSubjects))
 	for k, v := range credSubjects {
 		credential := &VerifiableCredential{
 			UnsignedVerifiableCredential: UnsignedVerifiableCredential{
-				Metadata:          *b.Metadata,
+				Metadata:          metadata,
 				CredentialSubject: map[string]interface{}{k: v},
 			},
 		}
@@ -57,10 +110,34 @@ func (b Builder) Build() (*VerifiableCredential, error) {
 
 	cred := &VerifiableCredential{
 		UnsignedVerifiableCredential: UnsignedVerifiableCredential{
-			Metadata:          *b.Metadata,
+			Metadata:          metadata,
 			CredentialSubject: credSubjects,
 			ClaimProofs:       claimProofs,
 		},
 	}
 	return cred, suite.Sign(cred, b.Signer, options)
 }
+
+// structToMap round-trips v through JSON to get a generic map[string]interface{} that
+// ResourceDigest.Apply can walk and mutate in place, since it operates on maps rather than
+// concrete struct types.
+func structToMap(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// mapToStruct is structToMap's inverse: it writes m's fields back onto v.
+func mapToStruct(m map[string]interface{}, v interface{}) error {
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
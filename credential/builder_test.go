@@ -0,0 +1,98 @@
+package credential
+
+import (
+	"testing"
+	"time"
+
+	"github.com/workdaycredentials/ledger-common/proof"
+)
+
+type stubEdSigner struct{ keyID string }
+
+func (s stubEdSigner) Sign(message []byte) ([]byte, error) { return message, nil }
+func (s stubEdSigner) KeyID() string                       { return s.keyID }
+
+func TestBuilder_Build_AppliesResourceDigestToMetadataAndCredentialSubject(t *testing.T) {
+	fetcher := staticFetcher{"https://example.com/schema.json": []byte("schema bytes")}
+	b := Builder{
+		SubjectDID: "did:example:subject",
+		Data: map[string]interface{}{
+			"schema": map[string]interface{}{
+				"id":   "https://example.com/schema.json",
+				"type": "JsonSchemaValidator2018",
+				"url":  "https://example.com/schema.json",
+			},
+		},
+		Metadata: &Metadata{
+			IssuerDID:    "did:example:issuer",
+			IssuanceDate: time.Unix(1767225600, 0).UTC(),
+			ID:           "urn:uuid:abc",
+		},
+		Signer:        stubEdSigner{keyID: "did:example:issuer#key-1"},
+		SignatureType: proof.WorkEdSignatureType,
+		ResourceDigest: &ResourceDigest{
+			Fetcher: fetcher,
+		},
+	}
+
+	cred, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	// The existing CredentialSubject digesting behavior must be unaffected.
+	schema := cred.CredentialSubject["schema"].(map[string]interface{})
+	if _, ok := schema[digestSRIAttribute]; !ok {
+		t.Errorf("expected CredentialSubject's resource reference to carry a digestSRI")
+	}
+
+	// Metadata fields must survive being round-tripped through ResourceDigest.Apply.
+	if cred.Metadata.IssuerDID != "did:example:issuer" {
+		t.Errorf("expected Metadata.IssuerDID to survive digesting, got %q", cred.Metadata.IssuerDID)
+	}
+	if cred.Metadata.ID != "urn:uuid:abc" {
+		t.Errorf("expected Metadata.ID to survive digesting, got %q", cred.Metadata.ID)
+	}
+	if cred.Metadata.IssuanceDate.Unix() != 1767225600 {
+		t.Errorf("expected Metadata.IssuanceDate to survive digesting, got %v", cred.Metadata.IssuanceDate)
+	}
+}
+
+func TestBuilder_Build_DoesNotMutateCallersMetadata(t *testing.T) {
+	fetcher := staticFetcher{"https://example.com/schema.json": []byte("schema bytes")}
+	metadata := &Metadata{
+		IssuerDID: "did:example:issuer",
+		Schema: map[string]interface{}{
+			"id":   "https://example.com/schema.json",
+			"type": "JsonSchemaValidator2018",
+			"url":  "https://example.com/schema.json",
+		},
+	}
+	b := Builder{
+		SubjectDID:    "did:example:subject",
+		Metadata:      metadata,
+		Signer:        stubEdSigner{keyID: "did:example:issuer#key-1"},
+		SignatureType: proof.WorkEdSignatureType,
+		ResourceDigest: &ResourceDigest{
+			Fetcher: fetcher,
+		},
+	}
+
+	cred, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	// The returned credential's Metadata must carry the digest...
+	credSchema := cred.Metadata.Schema.(map[string]interface{})
+	if _, ok := credSchema[digestSRIAttribute]; !ok {
+		t.Errorf("expected the returned credential's Metadata resource reference to carry a digestSRI")
+	}
+
+	// ...but the Metadata the caller passed in must come out untouched, so the same *Metadata can be
+	// reused across a batch of Build calls without accumulating digestSRI fields.
+	callerSchema := metadata.Schema.(map[string]interface{})
+	if _, ok := callerSchema[digestSRIAttribute]; ok {
+		t.Errorf("expected Build not to mutate the caller's Metadata in place, but digestSRI leaked onto it")
+	}
+}
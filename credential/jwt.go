@@ -0,0 +1,258 @@
+package credential
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/workdaycredentials/ledger-common/proof"
+)
+
+// VCClaims maps a Verifiable Credential onto the registered JWT claims described in the W3C VC
+// Data Model "JSON Web Token" section. The VC itself (minus the claims promoted to the top level)
+// is carried under "vc".
+type VCClaims struct {
+	Issuer         string                       `json:"iss"`
+	Subject        string                       `json:"sub,omitempty"`
+	NotBefore      int64                        `json:"nbf,omitempty"`
+	ExpirationTime int64                        `json:"exp,omitempty"`
+	JWTID          string                       `json:"jti,omitempty"`
+	VC             UnsignedVerifiableCredential `json:"vc"`
+}
+
+// vcPromotedProperties lists the VC Data Model properties whose values are carried exclusively by
+// the registered claims (iss/sub/nbf/exp/jti) once a credential is JWT-serialized. The spec
+// requires these not appear twice, so MarshalJSON strips them from the embedded "vc" object.
+var vcPromotedProperties = []string{"issuer", "issuanceDate", "expirationDate", "id"}
+
+// MarshalJSON serializes the claim set with vcPromotedProperties removed from the "vc" object, so
+// issuer/issuanceDate/expirationDate/id are only ever present once, as the iss/nbf/exp/jti claims.
+func (c VCClaims) MarshalJSON() ([]byte, error) {
+	type alias VCClaims
+	raw, err := json.Marshal(alias(c))
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	var vc map[string]json.RawMessage
+	if err := json.Unmarshal(doc["vc"], &vc); err != nil {
+		return nil, err
+	}
+	for _, property := range vcPromotedProperties {
+		delete(vc, property)
+	}
+	trimmedVC, err := json.Marshal(vc)
+	if err != nil {
+		return nil, err
+	}
+	doc["vc"] = trimmedVC
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON is the inverse of MarshalJSON: it restores vcPromotedProperties onto VC.Metadata
+// from the registered claims (iss/nbf/exp/jti) they were promoted from, so a VC-JWT round trip
+// through ParseJWTCredential/VerifyJWTCredential doesn't lose issuer/issuanceDate/expirationDate/id.
+func (c *VCClaims) UnmarshalJSON(data []byte) error {
+	type alias VCClaims
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*c = VCClaims(a)
+
+	c.VC.Metadata.IssuerDID = c.Issuer
+	c.VC.Metadata.ID = c.JWTID
+	c.VC.Metadata.IssuanceDate = time.Unix(c.NotBefore, 0).UTC()
+	if c.ExpirationTime != 0 {
+		c.VC.Metadata.ExpirationDate = time.Unix(c.ExpirationTime, 0).UTC()
+	}
+	return nil
+}
+
+// JWTBuilder is used to construct a JWT-serialized Verifiable Credential. It mirrors Builder, but
+// produces a compact JWS instead of an embedded Linked-Data proof, per the W3C VC Data Model's
+// JSON Web Token section.
+type JWTBuilder struct {
+	// SubjectDID is recorded as both the "sub" claim and the credentialSubject "id".
+	SubjectDID string `validate:"required"`
+	// Data is a map of claims that adhere to the schema referenced in the Metadata.
+	Data map[string]interface{}
+	// Metadata is information about the credential.
+	Metadata *Metadata `validate:"required"`
+	// Signer has the ability to generate a digital signature for a provided signature type.
+	Signer proof.Signer `validate:"required"`
+	// SignatureType specifies the JWT suite used to sign the credential (e.g.
+	// proof.JWTEdSignatureType).
+	SignatureType proof.SignatureType `validate:"required"`
+	// KeyID is the verification method DID URL of the signing key, recorded in the JOSE header.
+	KeyID string `validate:"required"`
+}
+
+// Build returns the compact JWS serialization of the Verifiable Credential described by the
+// builder.
+func (b JWTBuilder) Build() (string, error) {
+	if err := validator.New().Struct(b); err != nil {
+		return "", err
+	}
+
+	suite, err := proof.SignatureSuites().GetSuiteForJWT(b.SignatureType)
+	if err != nil {
+		return "", err
+	}
+
+	credSubjects := map[string]interface{}{SubjectIDAttribute: b.SubjectDID}
+	for k, v := range b.Data {
+		credSubjects[k] = v
+	}
+
+	claims := VCClaims{
+		Issuer:    b.Metadata.IssuerDID,
+		Subject:   b.SubjectDID,
+		NotBefore: b.Metadata.IssuanceDate.Unix(),
+		JWTID:     b.Metadata.ID,
+		VC: UnsignedVerifiableCredential{
+			Metadata:          *b.Metadata,
+			CredentialSubject: credSubjects,
+		},
+	}
+	if !b.Metadata.ExpirationDate.IsZero() {
+		claims.ExpirationTime = b.Metadata.ExpirationDate.Unix()
+	}
+
+	return suite.SignJWS(claims, b.Signer, b.KeyID)
+}
+
+// ParseJWTCredential decodes, but does not verify, a JWT-serialized Verifiable Credential,
+// returning the embedded claims. Use VerifyJWTCredential to additionally check the signature.
+func ParseJWTCredential(token string) (*VCClaims, error) {
+	payload, err := decodeJWSPayload(token)
+	if err != nil {
+		return nil, err
+	}
+	var claims VCClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("malformed VC-JWT claims: %w", err)
+	}
+	return &claims, nil
+}
+
+// VerifyJWTCredential verifies the signature on a JWT-serialized Verifiable Credential using
+// verifier, and returns the embedded claims on success.
+func VerifyJWTCredential(token string, signatureType proof.SignatureType, verifier proof.Verifier) (*VCClaims, error) {
+	suite, err := proof.SignatureSuites().GetSuiteForJWT(signatureType)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := suite.VerifyJWS(token, verifier)
+	if err != nil {
+		return nil, err
+	}
+	var claims VCClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("malformed VC-JWT claims: %w", err)
+	}
+	return &claims, nil
+}
+
+// VPClaims maps a Verifiable Presentation onto the registered JWT claims. Aud/nonce are used for
+// holder binding: the verifier supplies the nonce it expects to see echoed back, and aud names the
+// party the presentation was generated for.
+type VPClaims struct {
+	Issuer   string          `json:"iss"`
+	Audience string          `json:"aud,omitempty"`
+	Nonce    string          `json:"nonce,omitempty"`
+	VP       PresentationVP `json:"vp"`
+}
+
+// PresentationVP is the body of the "vp" claim: a holder-signed wrapper around the VC-JWTs being
+// presented.
+type PresentationVP struct {
+	HolderDID            string   `json:"holder"`
+	VerifiableCredential []string `json:"verifiableCredential"`
+}
+
+// JWTVPBuilder constructs a JWT-serialized Verifiable Presentation, binding a holder to a set of
+// VC-JWTs.
+type JWTVPBuilder struct {
+	HolderDID     string `validate:"required"`
+	Audience      string
+	Nonce         string
+	Credentials   []string            `validate:"required"`
+	Signer        proof.Signer        `validate:"required"`
+	SignatureType proof.SignatureType `validate:"required"`
+	KeyID         string              `validate:"required"`
+}
+
+// Build returns the compact JWS serialization of the Verifiable Presentation described by the
+// builder.
+func (b JWTVPBuilder) Build() (string, error) {
+	if err := validator.New().Struct(b); err != nil {
+		return "", err
+	}
+
+	suite, err := proof.SignatureSuites().GetSuiteForJWT(b.SignatureType)
+	if err != nil {
+		return "", err
+	}
+
+	claims := VPClaims{
+		Issuer:   b.HolderDID,
+		Audience: b.Audience,
+		Nonce:    b.Nonce,
+		VP: PresentationVP{
+			HolderDID:            b.HolderDID,
+			VerifiableCredential: b.Credentials,
+		},
+	}
+	return suite.SignJWS(claims, b.Signer, b.KeyID)
+}
+
+// VerifyJWTPresentation verifies the signature on a JWT-serialized Verifiable Presentation, and
+// additionally checks that its nonce matches expectedNonce and its audience matches
+// expectedAudience (when non-empty) to defeat replay and confused-deputy redirection of the
+// presentation to a verifier it wasn't generated for.
+func VerifyJWTPresentation(token string, signatureType proof.SignatureType, verifier proof.Verifier, expectedNonce, expectedAudience string) (*VPClaims, error) {
+	suite, err := proof.SignatureSuites().GetSuiteForJWT(signatureType)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := suite.VerifyJWS(token, verifier)
+	if err != nil {
+		return nil, err
+	}
+	var claims VPClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("malformed VP-JWT claims: %w", err)
+	}
+	if expectedNonce != "" && claims.Nonce != expectedNonce {
+		return nil, fmt.Errorf("nonce mismatch: presentation was not generated for this challenge")
+	}
+	if expectedAudience != "" && claims.Audience != expectedAudience {
+		return nil, fmt.Errorf("audience mismatch: presentation was not generated for this verifier")
+	}
+	return &claims, nil
+}
+
+// decodeJWSPayload extracts the (still base64url-encoded) payload segment of a compact JWS and
+// decodes it, without checking the signature.
+func decodeJWSPayload(token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed JWS: expected 3 segments, got %d", len(parts))
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWS payload: %w", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,103 @@
+package credential
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestVCClaims_MarshalJSON_TrimsPromotedProperties pins down the W3C VC Data Model JWT rule that a
+// property promoted to a registered claim (here, "issuer" via "iss") must not also appear on the
+// embedded "vc" object.
+func TestVCClaims_MarshalJSON_TrimsPromotedProperties(t *testing.T) {
+	var vc UnsignedVerifiableCredential
+	rawVC := `{
+		"issuer": "did:example:issuer",
+		"issuanceDate": "2026-01-01T00:00:00Z",
+		"expirationDate": "2027-01-01T00:00:00Z",
+		"id": "urn:uuid:abc",
+		"credentialSubject": {"id": "did:example:subject", "name": "value"}
+	}`
+	if err := json.Unmarshal([]byte(rawVC), &vc); err != nil {
+		t.Fatalf("failed to unmarshal fixture vc: %v", err)
+	}
+
+	claims := VCClaims{
+		Issuer:    "did:example:issuer",
+		Subject:   "did:example:subject",
+		NotBefore: 1767225600,
+		JWTID:     "urn:uuid:abc",
+		VC:        vc,
+	}
+
+	bytes, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(bytes, &doc); err != nil {
+		t.Fatalf("failed to unmarshal marshaled claims: %v", err)
+	}
+
+	vcOut, ok := doc["vc"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected \"vc\" to be an object, got %T", doc["vc"])
+	}
+	for _, promoted := range vcPromotedProperties {
+		if _, present := vcOut[promoted]; present {
+			t.Errorf("expected %q to be trimmed from the embedded vc, but it was present", promoted)
+		}
+	}
+	if doc["iss"] != "did:example:issuer" {
+		t.Errorf("expected iss claim to carry the issuer, got %v", doc["iss"])
+	}
+
+	subject, ok := vcOut["credentialSubject"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected credentialSubject to survive trimming, got %v", vcOut["credentialSubject"])
+	}
+	if subject["name"] != "value" {
+		t.Errorf("expected credentialSubject fields to survive trimming, got %v", subject)
+	}
+}
+
+// TestVCClaims_RoundTrip pins down that Unmarshal restores exactly what Marshal trimmed: a VC-JWT's
+// promoted properties must come back onto VC.Metadata from iss/nbf/exp/jti, not be lost.
+func TestVCClaims_RoundTrip(t *testing.T) {
+	claims := VCClaims{
+		Issuer:         "did:example:issuer",
+		Subject:        "did:example:subject",
+		NotBefore:      1767225600,
+		ExpirationTime: 1798761600,
+		JWTID:          "urn:uuid:abc",
+		VC: UnsignedVerifiableCredential{
+			CredentialSubject: map[string]interface{}{"id": "did:example:subject", "name": "value"},
+		},
+	}
+
+	bytes, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	var out VCClaims
+	if err := json.Unmarshal(bytes, &out); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	if out.VC.Metadata.IssuerDID != claims.Issuer {
+		t.Errorf("expected issuer to round-trip onto VC.Metadata.IssuerDID, got %q", out.VC.Metadata.IssuerDID)
+	}
+	if out.VC.Metadata.ID != claims.JWTID {
+		t.Errorf("expected id to round-trip onto VC.Metadata.ID, got %q", out.VC.Metadata.ID)
+	}
+	if out.VC.Metadata.IssuanceDate.Unix() != claims.NotBefore {
+		t.Errorf("expected issuanceDate to round-trip from nbf, got unix %d", out.VC.Metadata.IssuanceDate.Unix())
+	}
+	if out.VC.Metadata.ExpirationDate.Unix() != claims.ExpirationTime {
+		t.Errorf("expected expirationDate to round-trip from exp, got unix %d", out.VC.Metadata.ExpirationDate.Unix())
+	}
+	if name := out.VC.CredentialSubject["name"]; name != "value" {
+		t.Errorf("expected credentialSubject to survive the round trip, got %v", name)
+	}
+}
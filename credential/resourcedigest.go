@@ -0,0 +1,189 @@
+package credential
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// DigestAlgorithm selects the hash function used by ResourceDigest.
+type DigestAlgorithm string
+
+const (
+	Sha256DigestAlgorithm DigestAlgorithm = "sha-256"
+	Sha512DigestAlgorithm DigestAlgorithm = "sha-512"
+)
+
+// digestSRIAttribute is the W3C-defined property used to record a resource's content digest on the
+// reference object that points at it.
+//
+// The W3C VC Data Model also defines a sibling "digestMultibase" property, encoding the same digest
+// under a self-describing multibase prefix instead of a SRI string. This package intentionally only
+// emits digestSRI: digestMultibase requires a multibase (RFC "Multibase" / base58-btc) encoder,
+// which isn't among this repo's existing dependencies, and digestSRI alone already gives
+// VerifyResourceDigests everything it needs to detect drift. Add digestMultibase support (and a
+// multibase dependency) if and when a consumer actually needs that encoding.
+const digestSRIAttribute = "digestSRI"
+
+// ResourceFetcher retrieves the bytes of an external resource referenced from a credential, so its
+// digest can be computed at issuance time or recomputed at verification time.
+type ResourceFetcher interface {
+	Fetch(url string) ([]byte, error)
+}
+
+// ResourceDigest walks a credential's claim data before signing, finds fields that reference an
+// external resource by URL, and annotates each reference with a content digest, so the signature
+// over the credential also covers the transitive closure of everything it points at.
+type ResourceDigest struct {
+	// Fetcher retrieves the bytes backing a reference's "url" field.
+	Fetcher ResourceFetcher
+	// Algorithm selects sha-256 or sha-512; defaults to sha-256 if empty.
+	Algorithm DigestAlgorithm
+}
+
+// isResourceReference reports whether node identifies an external resource: an "id"/"type" pair
+// (as any other credential reference object has) plus the "url" it can be fetched from. A bare
+// "url" field elsewhere in the claim data (e.g. a profile's homepage) is left untouched.
+func isResourceReference(node map[string]interface{}) (url string, ok bool) {
+	if _, hasID := node["id"]; !hasID {
+		return "", false
+	}
+	if _, hasType := node["type"]; !hasType {
+		return "", false
+	}
+	url, hasURL := node["url"].(string)
+	return url, hasURL
+}
+
+// Apply walks data and, for every isResourceReference object, fetches the referenced bytes and
+// records their digest as the digestSRI property on that object. It mutates data in place and
+// also returns it for convenience.
+func (r ResourceDigest) Apply(data map[string]interface{}) (map[string]interface{}, error) {
+	if r.Fetcher == nil {
+		return nil, fmt.Errorf("resource digest: no Fetcher configured")
+	}
+	algorithm := r.Algorithm
+	if algorithm == "" {
+		algorithm = Sha256DigestAlgorithm
+	}
+
+	var walk func(v interface{}) error
+	walk = func(v interface{}) error {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			if url, ok := isResourceReference(node); ok {
+				bytes, err := r.Fetcher.Fetch(url)
+				if err != nil {
+					return fmt.Errorf("failed to fetch resource %q: %w", url, err)
+				}
+				node[digestSRIAttribute] = computeDigestSRI(algorithm, bytes)
+			}
+			for _, child := range node {
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			for _, child := range node {
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := walk(map[string]interface{}(data)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// VerifyResourceDigests re-fetches (via fetcher) every isResourceReference object referenced from
+// cred's CredentialSubject or Metadata that carries a digestSRI property, recomputes its digest,
+// and returns an error naming the first reference whose content has drifted from what was signed.
+// Metadata is walked the same way CredentialSubject is because Builder.Build applies ResourceDigest
+// to both before signing (see structToMap's use in builder.go): a reference left only on Metadata
+// needs the same drift check, or it could change after issuance without being caught.
+func VerifyResourceDigests(cred *VerifiableCredential, fetcher ResourceFetcher) error {
+	if fetcher == nil {
+		return fmt.Errorf("resource digest: no ResourceFetcher configured")
+	}
+
+	var walk func(v interface{}) error
+	walk = func(v interface{}) error {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			url, hasURL := isResourceReference(node)
+			expected, hasDigest := node[digestSRIAttribute].(string)
+			if hasURL && hasDigest {
+				bytes, err := fetcher.Fetch(url)
+				if err != nil {
+					return fmt.Errorf("failed to fetch resource %q: %w", url, err)
+				}
+				algorithm, err := digestAlgorithmFromSRI(expected)
+				if err != nil {
+					return err
+				}
+				if actual := computeDigestSRI(algorithm, bytes); actual != expected {
+					return fmt.Errorf("resource %q failed digest verification: expected %s, got %s", url, expected, actual)
+				}
+			}
+			for _, child := range node {
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			for _, child := range node {
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := walk(map[string]interface{}(cred.CredentialSubject)); err != nil {
+		return err
+	}
+
+	metadataFields, err := structToMap(cred.Metadata)
+	if err != nil {
+		return err
+	}
+	return walk(metadataFields)
+}
+
+// sriAlgorithmName maps a DigestAlgorithm to the algorithm token used in an SRI string, which
+// (unlike DigestAlgorithm's own "sha-256"/"sha-512" values) must not contain a hyphen: SRI parses
+// everything up to the first "-" as the algorithm name.
+var sriAlgorithmName = map[DigestAlgorithm]string{
+	Sha256DigestAlgorithm: "sha256",
+	Sha512DigestAlgorithm: "sha512",
+}
+
+// computeDigestSRI returns a W3C Subresource Integrity (SRI) string, e.g. "sha256-<base64>".
+func computeDigestSRI(algorithm DigestAlgorithm, data []byte) string {
+	var sum []byte
+	switch algorithm {
+	case Sha512DigestAlgorithm:
+		s := sha512.Sum512(data)
+		sum = s[:]
+	default:
+		s := sha256.Sum256(data)
+		sum = s[:]
+	}
+	return fmt.Sprintf("%s-%s", sriAlgorithmName[algorithm], base64.StdEncoding.EncodeToString(sum))
+}
+
+func digestAlgorithmFromSRI(sri string) (DigestAlgorithm, error) {
+	for algorithm, name := range sriAlgorithmName {
+		if strings.HasPrefix(sri, name+"-") {
+			return algorithm, nil
+		}
+	}
+	return "", fmt.Errorf("unrecognized digest algorithm in %q", sri)
+}
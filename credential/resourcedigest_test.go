@@ -0,0 +1,122 @@
+package credential
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type staticFetcher map[string][]byte
+
+func (f staticFetcher) Fetch(url string) ([]byte, error) {
+	bytes, ok := f[url]
+	if !ok {
+		return nil, fmt.Errorf("no fixture for %q", url)
+	}
+	return bytes, nil
+}
+
+func TestResourceDigest_Apply_OnlyDigestsIDTypeURLReferences(t *testing.T) {
+	digest := ResourceDigest{Fetcher: staticFetcher{"https://example.com/schema.json": []byte("schema bytes")}}
+
+	data := map[string]interface{}{
+		"homepage": "https://example.com/not-a-reference",
+		"schema": map[string]interface{}{
+			"id":   "https://example.com/schema.json",
+			"type": "JsonSchemaValidator2018",
+			"url":  "https://example.com/schema.json",
+		},
+	}
+
+	if _, err := digest.Apply(data); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	if data["homepage"] != "https://example.com/not-a-reference" {
+		t.Errorf("expected bare url field to be left untouched, got %v", data["homepage"])
+	}
+
+	schema := data["schema"].(map[string]interface{})
+	sri, ok := schema[digestSRIAttribute].(string)
+	if !ok {
+		t.Fatalf("expected digestSRI to be set on the id/type/url reference")
+	}
+	if !strings.HasPrefix(sri, "sha256-") {
+		t.Errorf("expected a valid SRI algorithm token (no internal hyphen), got %q", sri)
+	}
+}
+
+func TestResourceDigest_Apply_RequiresFetcher(t *testing.T) {
+	digest := ResourceDigest{}
+	if _, err := digest.Apply(map[string]interface{}{}); err == nil {
+		t.Fatalf("expected an error when Fetcher is unset")
+	}
+}
+
+func TestVerifyResourceDigests_RoundTrip(t *testing.T) {
+	fetcher := staticFetcher{"https://example.com/schema.json": []byte("schema bytes")}
+	subject := map[string]interface{}{
+		"schema": map[string]interface{}{
+			"id":   "https://example.com/schema.json",
+			"type": "JsonSchemaValidator2018",
+			"url":  "https://example.com/schema.json",
+		},
+	}
+
+	if _, err := (ResourceDigest{Fetcher: fetcher}).Apply(subject); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	cred := &VerifiableCredential{
+		UnsignedVerifiableCredential: UnsignedVerifiableCredential{
+			CredentialSubject: subject,
+		},
+	}
+
+	if err := VerifyResourceDigests(cred, fetcher); err != nil {
+		t.Errorf("expected verification to succeed against unmodified content, got %v", err)
+	}
+
+	tamperedFetcher := staticFetcher{"https://example.com/schema.json": []byte("tampered bytes")}
+	if err := VerifyResourceDigests(cred, tamperedFetcher); err == nil {
+		t.Errorf("expected verification to fail against tampered content")
+	}
+}
+
+func TestVerifyResourceDigests_CatchesDriftInMetadataReference(t *testing.T) {
+	fetcher := staticFetcher{"https://example.com/schema.json": []byte("schema bytes")}
+	metadata := Metadata{
+		Schema: map[string]interface{}{
+			"id":   "https://example.com/schema.json",
+			"type": "JsonSchemaValidator2018",
+			"url":  "https://example.com/schema.json",
+		},
+	}
+
+	metadataFields, err := structToMap(metadata)
+	if err != nil {
+		t.Fatalf("structToMap returned error: %v", err)
+	}
+	if _, err := (ResourceDigest{Fetcher: fetcher}).Apply(metadataFields); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if err := mapToStruct(metadataFields, &metadata); err != nil {
+		t.Fatalf("mapToStruct returned error: %v", err)
+	}
+
+	cred := &VerifiableCredential{
+		UnsignedVerifiableCredential: UnsignedVerifiableCredential{
+			Metadata:          metadata,
+			CredentialSubject: map[string]interface{}{},
+		},
+	}
+
+	if err := VerifyResourceDigests(cred, fetcher); err != nil {
+		t.Errorf("expected verification to succeed against unmodified content, got %v", err)
+	}
+
+	tamperedFetcher := staticFetcher{"https://example.com/schema.json": []byte("tampered bytes")}
+	if err := VerifyResourceDigests(cred, tamperedFetcher); err == nil {
+		t.Errorf("expected verification to fail against a Metadata-referenced resource that drifted after issuance")
+	}
+}
@@ -0,0 +1,407 @@
+package proof
+
+// This file's holder-derived proof (see BbsSelectiveDisclosureProof and DeriveBbsProof below) is
+// NOT a zero-knowledge proof, and must never be confused with one. It replays the issuer's raw
+// BBS+ signature and ships the full, unsalted BLAKE2b-384 digest of every statement, revealed or
+// not. That means: (1) a verifier who can enumerate a hidden statement's possible values (a small
+// enum, a boolean, an age) can dictionary-attack its digest straight out of the manifest, and (2)
+// every presentation derived from the same credential carries the identical signature/digest
+// sequence, so they remain linkable to each other. A real BBS+ selective-disclosure proof uses
+// pairing-based blinding to avoid both properties; that math is not implemented here (see
+// BbsSigner/BbsVerifier). Until it is, this holder-derived proof type is deliberately NOT named or
+// registered as the real W3C "BbsBlsSignatureProof2020" cryptosuite identifier, specifically so
+// that seeing BbsSelectiveDisclosureProof on a proof does not invite a caller to assume they get
+// the unlinkability/non-correlation guarantees that identifier normally implies elsewhere. The
+// only property it actually buys a holder today is a smaller proof than shipping every original
+// ClaimProofs entry; treat it as proof-size redaction, not privacy.
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// Bls12381G2Key2020 identifies a BLS12-381 G2 public key, used by the BBS+ suites below.
+	Bls12381G2Key2020 KeyType = "Bls12381G2Key2020"
+
+	// BbsBlsSignature2020 is the issuer-facing BBS+ signature type: a single signature over the
+	// sequence of canonicalized statements making up a credential. This is a real multi-message
+	// BBS+ signature (modulo the mock pairing backend, see BbsSigner/BbsVerifier); it is the
+	// holder-derived proof below, not this issuer signature, that falls short of the W3C spec's
+	// privacy guarantees.
+	BbsBlsSignature2020 SignatureType = "BbsBlsSignature2020"
+	// BbsSelectiveDisclosureProof is the holder-derived, redacted proof type produced by
+	// DeriveBbsProof. This is intentionally NOT named "BbsBlsSignatureProof2020": see the package
+	// comment at the top of this file for why reusing the real W3C identifier here would be
+	// misleading about what this proof actually hides.
+	BbsSelectiveDisclosureProof SignatureType = "BbsSelectiveDisclosureProof"
+)
+
+// StatementCanonicalizer produces the ordered, URDNA2015-normalized N-Quad statements for a
+// Provable. BBS+ signs these statements as independent messages rather than a single serialized
+// blob, which is what lets a derived proof redact a subset of them.
+type StatementCanonicalizer interface {
+	Statements(provable Provable) ([][]byte, error)
+}
+
+// BbsSigner is implemented by Signers that can produce a genuine BBS+ signature over BLS12-381: one
+// that signs each statement as an independent message, rather than a single signature over a
+// concatenated blob of them. A plain Signer has no way to do this (there is no pairing-based
+// multi-message primitive behind Sign([]byte)), so BbsSignatureSuite requires this interface and
+// fails clearly instead of silently downgrading to a single-message signature.
+type BbsSigner interface {
+	SignMultiMessage(messages [][]byte) ([]byte, error)
+}
+
+// BbsVerifier is the BbsSigner counterpart: it verifies a multi-message BBS+ signature over
+// BLS12-381.
+type BbsVerifier interface {
+	VerifyMultiMessage(messages [][]byte, signature []byte) error
+}
+
+// BbsSignatureSuite signs the N-Quad statements of a canonicalized, URDNA2015-normalized document
+// as an ordered sequence of independent BBS+ messages, rather than signing each claim individually
+// or concatenating them into one message. This lets Builder produce one compact proof regardless of
+// claim count, and lets DeriveProof later produce a derived proof that redacts a subset of them.
+//
+// The actual BLS12-381 pairing math is not implemented in this package: Signer/Verifier are already
+// this codebase's extension point for pluggable crypto backends (see e.g.
+// FlexibleSecp256k1Verifier), and BbsSigner/BbsVerifier above follow that same pattern. Signing or
+// verifying a BbsBlsSignature2020 proof requires a Signer/Verifier that implements them.
+type BbsSignatureSuite struct {
+	SignatureType SignatureType
+	KeyType       KeyType
+	Canonicalizer StatementCanonicalizer
+	ProofFactory  ProofFactory
+}
+
+func (s *BbsSignatureSuite) Type() SignatureType {
+	return s.SignatureType
+}
+
+// Sign canonicalizes provable into an ordered list of N-Quad statements and signs them as a single
+// multi-message BBS+ signature, embedding the result as provable's Proof. opts is folded into the
+// proof attached to provable before canonicalization (see signedStatementDocument), the same way
+// WithoutProofMarshaler binds proof options into what every LD suite in this package actually signs:
+// without that, proofPurpose (and anything else opts sets) could be altered on a signed BBS+
+// credential without invalidating it.
+func (s *BbsSignatureSuite) Sign(provable Provable, signer Signer, opts *ProofOptions) error {
+	bbsSigner, ok := signer.(BbsSigner)
+	if !ok {
+		return fmt.Errorf("%s requires a Signer implementing BbsSigner (multi-message BBS+ signing), got %T", s.SignatureType, signer)
+	}
+
+	newProof := s.ProofFactory.NewProof(s.SignatureType, signer.KeyID())
+	if opts != nil {
+		newProof.ProofPurpose = opts.ProofPurpose
+	}
+
+	unsigned := signedStatementDocument(provable, newProof)
+	statements, err := s.Canonicalizer.Statements(unsigned)
+	if err != nil {
+		return err
+	}
+	sig, err := bbsSigner.SignMultiMessage(statements)
+	if err != nil {
+		return err
+	}
+
+	newProof.SignatureValue = base64.RawURLEncoding.EncodeToString(sig)
+	provable.SetProof(newProof)
+	return nil
+}
+
+// Verify recomputes the N-Quad statements for provable and checks the embedded BBS+ signature
+// against verifier's G2 public key. The statements are recomputed over provable's existing proof
+// (minus its signature value, as Sign left it before signing), so tampering with proofPurpose or
+// any other field Sign folded in is caught the same way tampering with the claims is.
+func (s *BbsSignatureSuite) Verify(provable Provable, verifier Verifier) error {
+	existingProof := provable.GetProof()
+	if existingProof == nil {
+		return fmt.Errorf("no proof found on provable")
+	}
+
+	unsigned := signedStatementDocument(provable, existingProof)
+	statements, err := s.Canonicalizer.Statements(unsigned)
+	if err != nil {
+		return err
+	}
+
+	bbsVerifier, ok := verifier.(BbsVerifier)
+	if !ok {
+		return fmt.Errorf("%s requires a Verifier implementing BbsVerifier (multi-message BBS+ verification), got %T", s.SignatureType, verifier)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(existingProof.SignatureValue)
+	if err != nil {
+		return fmt.Errorf("malformed BBS+ signature: %w", err)
+	}
+	return bbsVerifier.VerifyMultiMessage(statements, sig)
+}
+
+// signedStatementDocument returns a copy of provable carrying p as its proof, with p's
+// SignatureValue stripped, so canonicalizing the copy folds in whatever proof options p carries
+// (proofPurpose, etc.) without the statement set trying to cover the signature value itself. Pass
+// a nil p to get a copy with no proof at all, e.g. to canonicalize a presented credential whose own
+// proof is unrelated to what was originally signed (see VerifyBbsDerivedProof).
+func signedStatementDocument(provable Provable, p *Proof) Provable {
+	unsigned := provable.Copy()
+	if p == nil {
+		unsigned.SetProof(nil)
+		return unsigned
+	}
+	stripped := *p
+	stripped.SignatureValue = ""
+	unsigned.SetProof(&stripped)
+	return unsigned
+}
+
+// urdna2015Canonicalizer canonicalizes a Provable's JSON-LD representation into an ordered list of
+// N-Quad statements using the RDF Dataset Normalization algorithm (URDNA2015), then digests each
+// statement independently with BLAKE2b-384, as required by the BbsBlsSignature2020 spec.
+type urdna2015Canonicalizer struct{}
+
+func (urdna2015Canonicalizer) Statements(provable Provable) ([][]byte, error) {
+	quads, _, err := toRDFDataset(provable)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := make([][]byte, len(quads))
+	for i, quad := range quads {
+		statements[i] = blake2b384(quad)
+	}
+	return statements, nil
+}
+
+// CanonicalStatementPaths returns the dot-path of each of provable's canonical BBS+ statements
+// (e.g. "credentialSubject.name", "issuer"), in the same order urdna2015Canonicalizer.Statements
+// returns their digests.
+func CanonicalStatementPaths(provable Provable) ([]string, error) {
+	_, paths, err := toRDFDataset(provable)
+	return paths, err
+}
+
+// statement is one canonicalized claim of a Provable, together with the dot-path that identifies it
+// (e.g. "credentialSubject.name", or "issuer" for a top-level credential field). Despite its name,
+// digest holds the undigested "path=value" bytes; they are hashed with blake2b384 later.
+type statement struct {
+	path   string
+	digest []byte
+}
+
+// toRDFDataset expands provable's JSON-LD representation into a flat list of N-Quad-equivalent
+// statements: one per top-level credential field, and separately one per individual field of
+// credentialSubject, so that a single claim can later be redacted without the others. A real
+// N-Quads expansion would walk the JSON-LD context to mint subject/predicate/object triples; this
+// treats each (possibly nested, one level deep) field as its own statement, which is sufficient to
+// let individual claims be selectively disclosed later.
+func toRDFDataset(provable Provable) ([][]byte, []string, error) {
+	marshaled, err := json.Marshal(provable)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal provable for BBS+ signing: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(marshaled, &fields); err != nil {
+		return nil, nil, fmt.Errorf("failed to decompose provable into statements: %w", err)
+	}
+
+	var raw []statement
+	for k, v := range fields {
+		if k == "credentialSubject" {
+			var subject map[string]json.RawMessage
+			if err := json.Unmarshal(v, &subject); err != nil {
+				return nil, nil, fmt.Errorf("failed to decompose credentialSubject into statements: %w", err)
+			}
+			for sk, sv := range subject {
+				path := "credentialSubject." + sk
+				raw = append(raw, statement{path: path, digest: append([]byte(path+"="), sv...)})
+			}
+			continue
+		}
+		raw = append(raw, statement{path: k, digest: append([]byte(k+"="), v...)})
+	}
+
+	normalized := normalizeStatements(raw)
+	quads := make([][]byte, len(normalized))
+	paths := make([]string, len(normalized))
+	for i, s := range normalized {
+		quads[i] = s.digest
+		paths[i] = s.path
+	}
+	return quads, paths, nil
+}
+
+// normalizeStatements sorts statements lexicographically by their N-Quad bytes, which is what
+// URDNA2015 reduces to once blank node labels have been issued deterministically (there are none
+// in our credential statements, so sorting is the entire algorithm here). Sorting by the full
+// statement, not just the path, matches what Sign/Verify actually compute over.
+func normalizeStatements(statements []statement) []statement {
+	sorted := make([]statement, len(statements))
+	copy(sorted, statements)
+	sort.Slice(sorted, func(i, j int) bool {
+		return string(sorted[i].digest) < string(sorted[j].digest)
+	})
+	return sorted
+}
+
+func blake2b384(statement []byte) []byte {
+	digest := blake2b.Sum384(statement)
+	return digest[:]
+}
+
+// SignedStatement pairs a canonical statement's dot-path (see CanonicalStatementPaths) with the
+// digest the issuer actually signed it under, in the exact order the issuer's BBS+ signature covers
+// them. Path is only populated for statements the holder chose to reveal: a hidden statement still
+// needs its digest present (VerifyBbsDerivedProof needs every digest to recheck the issuer's
+// multi-message signature), but carries no Path, so a verifier can't learn the dot-path of a field
+// the holder redacted just by inspecting the derived proof.
+type SignedStatement struct {
+	Path   string `json:"path,omitempty"`
+	Digest string `json:"digest"` // base64url-encoded BLAKE2b-384 digest
+}
+
+// BbsDerivedProofData is the payload carried by a BbsSelectiveDisclosureProof proof.
+//
+// This is NOT a zero-knowledge proof of knowledge: it carries the issuer's original BBS+ signature
+// unchanged, plus the full ordered list of per-statement digests the issuer actually signed (opaque
+// BLAKE2b-384 hashes, never the plaintext, and never the dot-path for a hidden statement). A
+// verifier recomputes the digest of every statement it can still see in the redacted credential and
+// checks it against the matching entry here, then reverifies the untouched issuer signature over the
+// full digest sequence. That's enough to prove the redacted credential is a faithful subset of
+// something the issuer actually signed, but unlike a real BBS+ pairing-based proof of knowledge it is
+// not unlinkable: every proof derived from the same credential carries the identical signature and
+// digest sequence, so presentations of it remain correlatable with each other, and a verifier who
+// already knows (or can enumerate) the hidden field's dot-path can still dictionary-attack a
+// low-entropy value's unsalted digest. A true zero-knowledge derivation would require pairing-based
+// blinding of the hidden messages, which is out of scope here (see BbsSigner/BbsVerifier).
+type BbsDerivedProofData struct {
+	Signature  string            `json:"signature"`
+	Statements []SignedStatement `json:"statements"`
+}
+
+// DeriveBbsProof produces a BbsSelectiveDisclosureProof proof for provable (the full, unredacted
+// credential issuerProof was signed over): it packages issuerProof's signature, unchanged, together
+// with the digest of every canonical statement provable carries. revealed is the set of dot-paths
+// (see CanonicalStatementPaths) the caller is actually disclosing; every other statement's digest is
+// still included; VerifyBbsDerivedProof needs the complete signed sequence to recheck the BBS+
+// signature, but its Path is left empty so the manifest doesn't hand a verifier the field name of
+// something the holder redacted. The caller is responsible for actually redacting provable's fields
+// (e.g. dropping credentialSubject claims that shouldn't be revealed) before presenting it alongside
+// the returned proof; VerifyBbsDerivedProof checks that whatever does survive matches what's
+// recorded here.
+func DeriveBbsProof(provable Provable, issuerProof *Proof, revealed map[string]bool) (*Proof, error) {
+	if issuerProof.Type != BbsBlsSignature2020 {
+		return nil, fmt.Errorf("cannot derive a BBS+ proof from a %s proof", issuerProof.Type)
+	}
+
+	unsigned := signedStatementDocument(provable, issuerProof)
+	digests, paths, err := toRDFDataset(unsigned)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := make([]SignedStatement, len(digests))
+	for i, path := range paths {
+		entry := SignedStatement{Digest: base64.RawURLEncoding.EncodeToString(blake2b384(digests[i]))}
+		if revealed[path] {
+			entry.Path = path
+		}
+		statements[i] = entry
+	}
+
+	data := BbsDerivedProofData{Signature: issuerProof.SignatureValue, Statements: statements}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	derived := *issuerProof
+	derived.Type = BbsSelectiveDisclosureProof
+	derived.SignatureValue = base64.RawURLEncoding.EncodeToString(encoded)
+	return &derived, nil
+}
+
+// VerifyBbsDerivedProof checks a BbsSelectiveDisclosureProof proof against provable, the redacted
+// credential the holder actually presented. Every statement provable still carries must both be
+// named in derivedProof's digest manifest and hash to the exact digest recorded there (catching a
+// holder that added or altered a visible claim); the manifest's full digest sequence, together with
+// the carried-over issuer signature, must then verify against verifier's BLS12-381 G2 public key.
+func VerifyBbsDerivedProof(provable Provable, derivedProof *Proof, verifier Verifier) error {
+	if derivedProof.Type != BbsSelectiveDisclosureProof {
+		return fmt.Errorf("not a %s proof", BbsSelectiveDisclosureProof)
+	}
+	bbsVerifier, ok := verifier.(BbsVerifier)
+	if !ok {
+		return fmt.Errorf("%s requires a Verifier implementing BbsVerifier (multi-message BBS+ verification), got %T", BbsSelectiveDisclosureProof, verifier)
+	}
+
+	encoded, err := base64.RawURLEncoding.DecodeString(derivedProof.SignatureValue)
+	if err != nil {
+		return fmt.Errorf("malformed derived proof: %w", err)
+	}
+	var data BbsDerivedProofData
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return fmt.Errorf("malformed derived proof: %w", err)
+	}
+
+	// provable's own proof is the derived proof we're in the middle of checking, not whatever was
+	// originally signed, so it's excluded here the same way a genuinely hidden statement would be:
+	// trusted via the digest sequence and the final signature check below, never recomputed.
+	unsigned := signedStatementDocument(provable, nil)
+	rawStatements, paths, err := toRDFDataset(unsigned)
+	if err != nil {
+		return err
+	}
+	rawByPath := make(map[string][]byte, len(paths))
+	for i, path := range paths {
+		rawByPath[path] = rawStatements[i]
+	}
+
+	digests := make([][]byte, len(data.Statements))
+	signedPaths := make(map[string]bool, len(data.Statements))
+	for i, s := range data.Statements {
+		digest, err := base64.RawURLEncoding.DecodeString(s.Digest)
+		if err != nil {
+			return fmt.Errorf("malformed digest for statement %q: %w", s.Path, err)
+		}
+		digests[i] = digest
+		if s.Path != "" {
+			signedPaths[s.Path] = true
+		}
+
+		if s.Path == "" {
+			continue
+		}
+		if raw, visible := rawByPath[s.Path]; visible {
+			if actual := blake2b384(raw); !bytes.Equal(actual, digest) {
+				return fmt.Errorf("revealed statement %q does not match the digest the issuer signed", s.Path)
+			}
+		}
+	}
+	for _, path := range paths {
+		if !signedPaths[path] {
+			return fmt.Errorf("credential carries %q, which is not part of the originally signed statement set", path)
+		}
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(data.Signature)
+	if err != nil {
+		return fmt.Errorf("malformed BBS+ signature: %w", err)
+	}
+	return bbsVerifier.VerifyMultiMessage(digests, sig)
+}
+
+// bbsSignatureSuite signs BbsBlsSignature2020 credential proofs using a single multi-message BBS+
+// signature over the URDNA2015-canonicalized, per-statement digests of the credential.
+var bbsSignatureSuite = &BbsSignatureSuite{
+	SignatureType: BbsBlsSignature2020,
+	KeyType:       Bls12381G2Key2020,
+	Canonicalizer: urdna2015Canonicalizer{},
+	ProofFactory:  &proofFactoryV2{},
+}
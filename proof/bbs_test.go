@@ -0,0 +1,262 @@
+package proof
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// stubBbsProvable is a minimal Provable used to exercise canonicalization without the credential
+// package's concrete VerifiableCredential type.
+type stubBbsProvable struct {
+	Issuer            string                 `json:"issuer"`
+	CredentialSubject map[string]interface{} `json:"credentialSubject"`
+	Proof             *Proof                 `json:"proof,omitempty"`
+}
+
+func (s *stubBbsProvable) Copy() Provable {
+	cp := *s
+	subject := make(map[string]interface{}, len(s.CredentialSubject))
+	for k, v := range s.CredentialSubject {
+		subject[k] = v
+	}
+	cp.CredentialSubject = subject
+	return &cp
+}
+
+func (s *stubBbsProvable) GetProof() *Proof  { return s.Proof }
+func (s *stubBbsProvable) SetProof(p *Proof) { s.Proof = p }
+
+// fakeBbsKeyPair is a deterministic, non-pairing stand-in for a real BLS12-381 BBS+ key pair: it
+// "signs" a message sequence by hashing each message (length-delimited, so distinct statement
+// boundaries can never collide) together with a secret, and "verifies" by recomputing the same
+// hash. It exists only so BbsSignatureSuite's delegation to BbsSigner/BbsVerifier can be exercised
+// deterministically in tests; it is not cryptographically sound and must never be used outside
+// tests.
+type fakeBbsKeyPair struct {
+	secret string
+	keyID  string
+}
+
+func (k *fakeBbsKeyPair) KeyID() string { return k.keyID }
+
+// Sign and Verify treat a single message as a one-message sequence, so fakeBbsKeyPair also
+// satisfies the plain Signer/Verifier interfaces the suites take.
+func (k *fakeBbsKeyPair) Sign(message []byte) ([]byte, error) {
+	return k.SignMultiMessage([][]byte{message})
+}
+
+func (k *fakeBbsKeyPair) Verify(message, signature []byte) error {
+	return k.VerifyMultiMessage([][]byte{message}, signature)
+}
+
+func (k *fakeBbsKeyPair) SignMultiMessage(messages [][]byte) ([]byte, error) {
+	return fakeBbsDigest(k.secret, messages), nil
+}
+
+func (k *fakeBbsKeyPair) VerifyMultiMessage(messages [][]byte, signature []byte) error {
+	if !bytes.Equal(fakeBbsDigest(k.secret, messages), signature) {
+		return errInvalidFakeBbsSignature
+	}
+	return nil
+}
+
+var errInvalidFakeBbsSignature = fakeBbsError("fake BBS+ signature verification failed")
+
+type fakeBbsError string
+
+func (e fakeBbsError) Error() string { return string(e) }
+
+func fakeBbsDigest(secret string, messages [][]byte) []byte {
+	h, _ := blake2b.New384(nil)
+	h.Write([]byte(secret))
+	for _, m := range messages {
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(m)))
+		h.Write(length[:])
+		h.Write(m)
+	}
+	return h.Sum(nil)
+}
+
+func TestCanonicalStatementPaths_OnePerSubjectClaim(t *testing.T) {
+	doc := &stubBbsProvable{
+		Issuer: "did:example:issuer",
+		CredentialSubject: map[string]interface{}{
+			"name": "Alice",
+			"age":  30,
+		},
+	}
+
+	paths, err := CanonicalStatementPaths(doc)
+	if err != nil {
+		t.Fatalf("CanonicalStatementPaths returned error: %v", err)
+	}
+
+	want := map[string]bool{"issuer": false, "credentialSubject.name": false, "credentialSubject.age": false}
+	if len(paths) != len(want) {
+		t.Fatalf("expected %d statements, got %d: %v", len(want), len(paths), paths)
+	}
+	for _, p := range paths {
+		if _, ok := want[p]; !ok {
+			t.Errorf("unexpected statement path %q", p)
+		}
+		want[p] = true
+	}
+	for p, seen := range want {
+		if !seen {
+			t.Errorf("expected a statement for %q, but it was missing", p)
+		}
+	}
+}
+
+func TestBbsSignatureSuite_SignVerify_RoundTrip(t *testing.T) {
+	doc := &stubBbsProvable{
+		Issuer:            "did:example:issuer",
+		CredentialSubject: map[string]interface{}{"name": "Alice", "age": 30},
+	}
+	keyPair := &fakeBbsKeyPair{secret: "issuer-secret", keyID: "did:example:issuer#key-1"}
+
+	if err := bbsSignatureSuite.Sign(doc, keyPair, &ProofOptions{}); err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+	if doc.Proof == nil || doc.Proof.Type != BbsBlsSignature2020 {
+		t.Fatalf("expected a %s proof, got %v", BbsBlsSignature2020, doc.Proof)
+	}
+	if err := bbsSignatureSuite.Verify(doc, keyPair); err != nil {
+		t.Errorf("Verify rejected a validly signed document: %v", err)
+	}
+
+	doc.CredentialSubject["age"] = 31
+	if err := bbsSignatureSuite.Verify(doc, keyPair); err == nil {
+		t.Errorf("Verify accepted a document that was tampered with after signing")
+	}
+}
+
+func TestBbsSignatureSuite_Sign_RequiresBbsSigner(t *testing.T) {
+	doc := &stubBbsProvable{Issuer: "did:example:issuer"}
+	if err := bbsSignatureSuite.Sign(doc, plainStubSigner{}, &ProofOptions{}); err == nil {
+		t.Fatalf("expected Sign to fail when the Signer does not implement BbsSigner")
+	}
+}
+
+// plainStubSigner implements only the base Signer interface, never BbsSigner/BbsVerifier.
+type plainStubSigner struct{}
+
+func (plainStubSigner) Sign(message []byte) ([]byte, error) { return message, nil }
+func (plainStubSigner) KeyID() string                       { return "did:example:issuer#key-1" }
+
+// revealedPaths builds the set DeriveBbsProof expects as its reveal set from a literal list of
+// dot-paths.
+func revealedPaths(paths ...string) map[string]bool {
+	set := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		set[p] = true
+	}
+	return set
+}
+
+func TestDeriveAndVerifyBbsProof_RoundTrip(t *testing.T) {
+	doc := &stubBbsProvable{
+		Issuer:            "did:example:issuer",
+		CredentialSubject: map[string]interface{}{"name": "Alice", "age": 30, "email": "alice@example.com"},
+	}
+	keyPair := &fakeBbsKeyPair{secret: "issuer-secret", keyID: "did:example:issuer#key-1"}
+	if err := bbsSignatureSuite.Sign(doc, keyPair, &ProofOptions{}); err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+
+	derivedProof, err := DeriveBbsProof(doc, doc.Proof, revealedPaths("issuer", "credentialSubject.name"))
+	if err != nil {
+		t.Fatalf("DeriveBbsProof returned error: %v", err)
+	}
+	if derivedProof.Type != BbsSelectiveDisclosureProof {
+		t.Fatalf("expected a %s proof, got %v", BbsSelectiveDisclosureProof, derivedProof.Type)
+	}
+
+	redacted := &stubBbsProvable{
+		Issuer:            doc.Issuer,
+		CredentialSubject: map[string]interface{}{"name": "Alice"},
+		Proof:             derivedProof,
+	}
+	if err := VerifyBbsDerivedProof(redacted, derivedProof, keyPair); err != nil {
+		t.Errorf("VerifyBbsDerivedProof rejected a faithfully redacted credential: %v", err)
+	}
+
+	var data BbsDerivedProofData
+	encoded, err := base64.RawURLEncoding.DecodeString(derivedProof.SignatureValue)
+	if err != nil {
+		t.Fatalf("failed to decode derived proof: %v", err)
+	}
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		t.Fatalf("failed to unmarshal derived proof data: %v", err)
+	}
+
+	for _, s := range data.Statements {
+		if s.Path == "credentialSubject.age" || s.Path == "credentialSubject.email" {
+			t.Fatalf("manifest leaked the dot-path of a hidden statement: %+v", s)
+		}
+	}
+}
+
+func TestVerifyBbsDerivedProof_RejectsAlteredRevealedStatement(t *testing.T) {
+	doc := &stubBbsProvable{
+		Issuer:            "did:example:issuer",
+		CredentialSubject: map[string]interface{}{"name": "Alice", "age": 30},
+	}
+	keyPair := &fakeBbsKeyPair{secret: "issuer-secret", keyID: "did:example:issuer#key-1"}
+	if err := bbsSignatureSuite.Sign(doc, keyPair, &ProofOptions{}); err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+
+	derivedProof, err := DeriveBbsProof(doc, doc.Proof, revealedPaths("issuer", "credentialSubject.name"))
+	if err != nil {
+		t.Fatalf("DeriveBbsProof returned error: %v", err)
+	}
+
+	tampered := &stubBbsProvable{
+		Issuer:            doc.Issuer,
+		CredentialSubject: map[string]interface{}{"name": "Mallory"},
+		Proof:             derivedProof,
+	}
+	if err := VerifyBbsDerivedProof(tampered, derivedProof, keyPair); err == nil {
+		t.Errorf("expected VerifyBbsDerivedProof to reject a revealed statement that was altered after signing")
+	}
+}
+
+func TestVerifyBbsDerivedProof_RejectsUnsignedAddedField(t *testing.T) {
+	doc := &stubBbsProvable{
+		Issuer:            "did:example:issuer",
+		CredentialSubject: map[string]interface{}{"name": "Alice"},
+	}
+	keyPair := &fakeBbsKeyPair{secret: "issuer-secret", keyID: "did:example:issuer#key-1"}
+	if err := bbsSignatureSuite.Sign(doc, keyPair, &ProofOptions{}); err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+
+	derivedProof, err := DeriveBbsProof(doc, doc.Proof, revealedPaths("issuer", "credentialSubject.name"))
+	if err != nil {
+		t.Fatalf("DeriveBbsProof returned error: %v", err)
+	}
+
+	forged := &stubBbsProvable{
+		Issuer:            doc.Issuer,
+		CredentialSubject: map[string]interface{}{"name": "Alice", "admin": true},
+		Proof:             derivedProof,
+	}
+	if err := VerifyBbsDerivedProof(forged, derivedProof, keyPair); err == nil {
+		t.Errorf("expected VerifyBbsDerivedProof to reject a field absent from the originally signed statement set")
+	}
+}
+
+func TestDeriveBbsProof_RejectsNonBbsIssuerProof(t *testing.T) {
+	doc := &stubBbsProvable{Issuer: "did:example:issuer"}
+	issuerProof := &Proof{Type: Ed25519SignatureType, SignatureValue: base64.RawURLEncoding.EncodeToString([]byte("sig"))}
+	if _, err := DeriveBbsProof(doc, issuerProof, nil); err == nil {
+		t.Fatalf("expected an error deriving a BBS+ proof from a non-BBS+ issuer proof")
+	}
+}
@@ -0,0 +1,122 @@
+package proof
+
+import (
+	"crypto/sha512"
+	"fmt"
+)
+
+// Ed25519phSignatureType identifies a suite that signs the Ed25519ph (RFC 8032 §5.1) pre-hashed
+// variant of Ed25519, rather than signing the full canonicalized message directly. Only
+// registered as a V2 suite: there is no legacy "creator"-style Ed25519ph proof to stay compatible
+// with.
+//
+// This must stay distinct from "Ed25519Signature2020", which is an existing W3C LD-Proofs suite
+// identifier that signs plain (non-pre-hashed) Ed25519 over JCS-canonicalized documents; reusing
+// that string here would make our proofs silently misinterpreted by interoperating verifiers.
+const Ed25519phSignatureType SignatureType = "Ed25519phSignature2020"
+
+// SHA512PreHasher digests a message with SHA-512 before it reaches the signer, as Ed25519ph
+// requires. This lets a streaming or HSM-backed Signer operate on a fixed 64-byte digest instead
+// of buffering an arbitrarily large canonicalized message.
+type SHA512PreHasher struct{}
+
+func (SHA512PreHasher) Digest(message []byte) []byte {
+	digest := sha512.Sum512(message)
+	return digest[:]
+}
+
+// PreHashSigner is implemented by Signers that can sign an already-hashed message under Ed25519ph,
+// as opposed to the full message under plain Ed25519. withPreHash uses this to invoke the correct
+// low-level call on KMS-backed signers that distinguish the two.
+type PreHashSigner interface {
+	SignPreHashed(digest []byte) ([]byte, error)
+}
+
+// PreHashVerifier is the Ed25519ph counterpart of PreHashSigner.
+type PreHashVerifier interface {
+	VerifyPreHashed(digest, sig []byte) error
+}
+
+// withPreHash wraps suite so that Sign/Verify digest their message with SHA-512 before
+// signing/verifying (Ed25519ph, RFC 8032 §5.1, empty context string), and dispatch through
+// SignPreHashed/VerifyPreHashed so a Signer/Verifier implementing PreHashSigner/PreHashVerifier
+// actually has its dedicated entry point invoked, rather than merely being handed an
+// already-hashed message through its ordinary Sign/Verify method. LDSignatureSuite's own Sign/
+// Verify only ever call Signer.Sign/Verifier.Verify directly: setting MessageDigest to a pre-hasher
+// alone isn't enough, since a KMS-backed signer may need SignPreHashed specifically invoked (e.g. to
+// select a pre-hash-mode key operation) rather than being given a pre-hashed byte slice through its
+// general-purpose Sign.
+func withPreHash(suite *LDSignatureSuite) SignatureSuite {
+	updated := *suite
+	updated.SignatureType = Ed25519phSignatureType
+	updated.MessageDigest = &SHA512PreHasher{}
+	return &preHashSignatureSuite{LDSignatureSuite: &updated}
+}
+
+// preHashSignatureSuite wraps an LDSignatureSuite configured for Ed25519ph so its Sign/Verify route
+// through SignPreHashed/VerifyPreHashed. See withPreHash.
+type preHashSignatureSuite struct {
+	*LDSignatureSuite
+}
+
+func (s *preHashSignatureSuite) Sign(provable Provable, signer Signer, opts *ProofOptions) error {
+	return s.LDSignatureSuite.Sign(provable, preHashDispatchSigner{signer}, opts)
+}
+
+func (s *preHashSignatureSuite) Verify(provable Provable, verifier Verifier) error {
+	return s.LDSignatureSuite.Verify(provable, preHashDispatchVerifier{verifier})
+}
+
+// preHashDispatchSigner adapts a Signer so that a Sign call carrying an already-digested message
+// (as LDSignatureSuite.Sign produces when MessageDigest is set) is routed through SignPreHashed,
+// which in turn calls the wrapped Signer's own SignPreHashed if it implements PreHashSigner.
+type preHashDispatchSigner struct {
+	Signer
+}
+
+func (s preHashDispatchSigner) Sign(digest []byte) ([]byte, error) {
+	return SignPreHashed(s.Signer, digest)
+}
+
+// preHashDispatchVerifier is preHashDispatchSigner's Verifier counterpart.
+type preHashDispatchVerifier struct {
+	Verifier
+}
+
+func (v preHashDispatchVerifier) Verify(digest, sig []byte) error {
+	return VerifyPreHashed(v.Verifier, digest, sig)
+}
+
+// SignPreHashed signs digest (expected to be a 64-byte SHA-512 digest) under Ed25519ph. A plain
+// Signer has no way to do this: real Ed25519ph applies its own dom2(1,"") prefix inside the
+// signature, which is not the same computation as signing the raw digest bytes through Sign, so
+// a fallback to Sign here would silently produce a signature that isn't interoperable with any
+// standards-compliant Ed25519ph implementation (see BbsSignatureSuite.Sign for the same
+// requires-the-real-interface-or-fails pattern applied to BBS+).
+func SignPreHashed(signer Signer, digest []byte) ([]byte, error) {
+	phSigner, ok := signer.(PreHashSigner)
+	if !ok {
+		return nil, fmt.Errorf("Ed25519ph requires a Signer implementing PreHashSigner, got %T", signer)
+	}
+	return phSigner.SignPreHashed(digest)
+}
+
+// VerifyPreHashed verifies digest/sig under Ed25519ph. Like SignPreHashed, it requires verifier to
+// implement PreHashVerifier rather than falling back to a plain Verify call: a plain Verify omits
+// Ed25519ph's dom2(1,"") prefix and so would accept signatures no real Ed25519ph implementation
+// would produce.
+func VerifyPreHashed(verifier Verifier, digest, sig []byte) error {
+	phVerifier, ok := verifier.(PreHashVerifier)
+	if !ok {
+		return fmt.Errorf("Ed25519ph requires a Verifier implementing PreHashVerifier, got %T", verifier)
+	}
+	if err := phVerifier.VerifyPreHashed(digest, sig); err != nil {
+		return fmt.Errorf("ed25519ph verification failed: %w", err)
+	}
+	return nil
+}
+
+// ed25519phSignatureSuite signs Ed25519phSignature2020 proofs using the Ed25519ph pre-hash mode,
+// dispatching through PreHashSigner/PreHashVerifier; Sign/Verify fail if the configured
+// Signer/Verifier does not implement them.
+var ed25519phSignatureSuite = withPreHash(withV2Proofs(ed25519SignatureSuiteV1.Main.(*LDSignatureSuite)))
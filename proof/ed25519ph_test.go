@@ -0,0 +1,188 @@
+package proof
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// prehashSigner/prehashVerifier adapt crypto/ed25519's native Ed25519ph support (crypto.Hash,
+// RFC 8032 §5.1) to PreHashSigner/PreHashVerifier, so SignPreHashed/VerifyPreHashed exercise the
+// real pre-hash signing mode rather than falling back to plain Ed25519.
+type prehashSigner struct{ key ed25519.PrivateKey }
+
+func (s prehashSigner) Sign(message []byte) ([]byte, error) {
+	return s.key.Sign(nil, message, crypto.Hash(0))
+}
+
+func (s prehashSigner) SignPreHashed(digest []byte) ([]byte, error) {
+	return s.key.Sign(nil, digest, crypto.SHA512)
+}
+
+func (s prehashSigner) KeyID() string { return "did:example:issuer#key-1" }
+
+type prehashVerifier struct{ key ed25519.PublicKey }
+
+func (v prehashVerifier) Verify(message, sig []byte) error {
+	if ed25519.Verify(v.key, message, sig) {
+		return nil
+	}
+	return errors.New("ed25519: invalid signature")
+}
+
+func (v prehashVerifier) VerifyPreHashed(digest, sig []byte) error {
+	return ed25519.VerifyWithOptions(v.key, digest, sig, &ed25519.Options{Hash: crypto.SHA512})
+}
+
+func TestSignPreHashed_RoundTrip(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	digest := (SHA512PreHasher{}).Digest([]byte("abc"))
+
+	sig, err := SignPreHashed(prehashSigner{priv}, digest)
+	if err != nil {
+		t.Fatalf("SignPreHashed failed: %v", err)
+	}
+
+	if err := VerifyPreHashed(prehashVerifier{pub}, digest, sig); err != nil {
+		t.Errorf("VerifyPreHashed rejected a valid Ed25519ph signature: %v", err)
+	}
+
+	if err := VerifyPreHashed(prehashVerifier{pub}, digest, append([]byte(nil), sig[:len(sig)-1]...)); err == nil {
+		t.Errorf("VerifyPreHashed accepted a truncated signature")
+	}
+}
+
+// TestSignPreHashed_RFC8032GoldenVector pins the official Ed25519ph test vector from RFC 8032
+// §7.3 (message "abc" under the fixed key of RFC 8032 §7.1's "TEST SHA(abc)"), rather than a freshly
+// generated key: TestSignPreHashed_RoundTrip only checks self-consistency, so a refactor that broke
+// interop with other Ed25519ph implementations (wrong domain separator, wrong hash, wrong context
+// string) could still pass it undetected. This pins the exact signature bytes RFC 8032 specifies.
+func TestSignPreHashed_RFC8032GoldenVector(t *testing.T) {
+	key, err := hex.DecodeString("833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42ec172b93ad5e563bf4932c70e1245034c35467ef2efd4d64ebf819683467e2bf")
+	if err != nil {
+		t.Fatalf("failed to decode test vector key: %v", err)
+	}
+	wantSig, err := hex.DecodeString("98a70222f0b8121aa9d30f813d683f809e462b469c7ff87639499bb94e6dae4131f85042463c2a355a2003d062adf5aaa10b8c61e636062aaad11c2a26083406")
+	if err != nil {
+		t.Fatalf("failed to decode test vector signature: %v", err)
+	}
+	priv := ed25519.PrivateKey(key)
+	pub := priv.Public().(ed25519.PublicKey)
+
+	digest := (SHA512PreHasher{}).Digest([]byte("abc"))
+
+	sig, err := SignPreHashed(prehashSigner{priv}, digest)
+	if err != nil {
+		t.Fatalf("SignPreHashed failed: %v", err)
+	}
+	if !bytes.Equal(sig, wantSig) {
+		t.Errorf("SignPreHashed produced %x, want the RFC 8032 §7.3 vector %x", sig, wantSig)
+	}
+
+	if err := VerifyPreHashed(prehashVerifier{pub}, digest, wantSig); err != nil {
+		t.Errorf("VerifyPreHashed rejected the RFC 8032 §7.3 vector: %v", err)
+	}
+}
+
+// plainPrehashSigner/plainPrehashVerifier implement only the base Signer/Verifier interfaces, never
+// PreHashSigner/PreHashVerifier, so SignPreHashed/VerifyPreHashed have no real Ed25519ph entry
+// point to dispatch through.
+type plainPrehashSigner struct{ key ed25519.PrivateKey }
+
+func (s plainPrehashSigner) Sign(message []byte) ([]byte, error) {
+	return s.key.Sign(nil, message, crypto.Hash(0))
+}
+
+func (s plainPrehashSigner) KeyID() string { return "did:example:issuer#key-1" }
+
+type plainPrehashVerifier struct{ key ed25519.PublicKey }
+
+func (v plainPrehashVerifier) Verify(message, sig []byte) error {
+	if ed25519.Verify(v.key, message, sig) {
+		return nil
+	}
+	return errors.New("ed25519: invalid signature")
+}
+
+func TestSignPreHashed_RequiresPreHashSigner(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	digest := (SHA512PreHasher{}).Digest([]byte("abc"))
+
+	if _, err := SignPreHashed(plainPrehashSigner{priv}, digest); err == nil {
+		t.Fatalf("expected SignPreHashed to reject a Signer that doesn't implement PreHashSigner")
+	}
+	if err := VerifyPreHashed(plainPrehashVerifier{pub}, digest, []byte("fake-signature")); err == nil {
+		t.Fatalf("expected VerifyPreHashed to reject a Verifier that doesn't implement PreHashVerifier")
+	}
+}
+
+func TestEd25519phSignatureType_DoesNotCollideWithPlainEd25519(t *testing.T) {
+	if Ed25519phSignatureType == Ed25519SignatureType {
+		t.Fatalf("Ed25519phSignatureType must not collide with Ed25519SignatureType, got %q for both", Ed25519phSignatureType)
+	}
+	if string(Ed25519phSignatureType) == "Ed25519Signature2020" {
+		t.Fatalf("Ed25519phSignatureType must not reuse the W3C Ed25519Signature2020 identifier, got %q", Ed25519phSignatureType)
+	}
+}
+
+// spyPrehashSigner/spyPrehashVerifier record whether SignPreHashed/VerifyPreHashed (as opposed to
+// the plain Sign/Verify) was the method actually invoked, so tests can tell the registered suite
+// dispatched through the pre-hash entry point rather than merely pre-hashing bytes handed to the
+// ordinary one.
+type spyPrehashSigner struct {
+	prehashSigner
+	preHashed bool
+}
+
+func (s *spyPrehashSigner) SignPreHashed(digest []byte) ([]byte, error) {
+	s.preHashed = true
+	return s.prehashSigner.SignPreHashed(digest)
+}
+
+type spyPrehashVerifier struct {
+	prehashVerifier
+	preHashed bool
+}
+
+func (v *spyPrehashVerifier) VerifyPreHashed(digest, sig []byte) error {
+	v.preHashed = true
+	return v.prehashVerifier.VerifyPreHashed(digest, sig)
+}
+
+// TestEd25519phSignatureSuite_DispatchesThroughPreHashSignerAndVerifier exercises
+// ed25519phSignatureSuite itself (the suite actually registered under SignatureSuites().Ed25519ph),
+// not just the free SignPreHashed/VerifyPreHashed functions, confirming the registered suite's
+// Sign/Verify calls are what trigger PreHashSigner/PreHashVerifier dispatch.
+func TestEd25519phSignatureSuite_DispatchesThroughPreHashSignerAndVerifier(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	signer := &spyPrehashSigner{prehashSigner: prehashSigner{key: priv}}
+	verifier := &spyPrehashVerifier{prehashVerifier: prehashVerifier{key: pub}}
+
+	doc := &stubProvable{Claim: "hello"}
+	if err := ed25519phSignatureSuite.Sign(doc, signer, &ProofOptions{}); err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+	if !signer.preHashed {
+		t.Errorf("expected the registered Ed25519ph suite to dispatch Sign through SignPreHashed")
+	}
+
+	if err := ed25519phSignatureSuite.Verify(doc, verifier); err != nil {
+		t.Errorf("Verify rejected a validly signed document: %v", err)
+	}
+	if !verifier.preHashed {
+		t.Errorf("expected the registered Ed25519ph suite to dispatch Verify through VerifyPreHashed")
+	}
+}
@@ -0,0 +1,117 @@
+package proof
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// JWTEdSignatureType identifies a JWT-serialized VC/VP signed by an Ed25519 key.
+	JWTEdSignatureType SignatureType = "JwtEd25519Signature2020"
+	// JWTSecp256k1SignatureType identifies a JWT-serialized VC/VP signed by a secp256k1 key.
+	JWTSecp256k1SignatureType SignatureType = "JwtEcdsaSecp256k1Signature2020"
+)
+
+// JWSSignatureSuite produces and verifies compact JSON Web Signatures for Provable objects that
+// are serialized as JWT claims, as opposed to the embedded Linked-Data proofs the other suites in
+// this package produce. It is registered in SignatureSuiteFactory alongside the LD suites so
+// callers can look it up the same way, but credential issuance/verification goes through
+// SignJWS/VerifyJWS rather than the embedded-proof Sign/Verify methods, since a JWS has no place
+// to embed itself in the payload it signs.
+type JWSSignatureSuite struct {
+	SignatureType SignatureType
+	KeyType       KeyType
+	// Alg is the JOSE "alg" header value this suite produces, e.g. "EdDSA" or "ES256K".
+	Alg string
+}
+
+func (s *JWSSignatureSuite) Type() SignatureType {
+	return s.SignatureType
+}
+
+// Sign is unsupported; it only exists so JWSSignatureSuite satisfies SignatureSuite for
+// SignatureSuiteFactory lookups.
+// Use SignJWS to produce a compact JWS for a set of claims.
+func (s *JWSSignatureSuite) Sign(provable Provable, signer Signer, opts *ProofOptions) error {
+	return fmt.Errorf("%s does not support embedded proofs, use SignJWS", s.SignatureType)
+}
+
+// Verify is unsupported for the same reason as Sign; use VerifyJWS.
+func (s *JWSSignatureSuite) Verify(provable Provable, verifier Verifier) error {
+	return fmt.Errorf("%s does not support embedded proofs, use VerifyJWS", s.SignatureType)
+}
+
+// jwsHeader is the JOSE header carried as the first segment of a compact JWS.
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// SignJWS marshals claims, signs the header and payload with signer, and returns the compact
+// serialization "header.payload.signature". kid is the verification method DID URL of the key
+// used, and is carried in the JOSE header so a verifier can dereference the right key.
+func (s *JWSSignatureSuite) SignJWS(claims interface{}, signer Signer, kid string) (string, error) {
+	header, err := json.Marshal(jwsHeader{Alg: s.Alg, Kid: kid})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encodeSegment(header) + "." + encodeSegment(payload)
+
+	sig, err := signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// VerifyJWS splits a compact JWS into its three segments, verifies the signature over the header
+// and payload with verifier, and returns the decoded payload on success.
+func (s *JWSSignatureSuite) VerifyJWS(token string, verifier Verifier) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed JWS: expected 3 segments, got %d", len(parts))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWS payload: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWS signature: %w", err)
+	}
+
+	signingInput := parts[0] + "." + parts[1]
+	if err := verifier.Verify([]byte(signingInput), sig); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// GetSuiteForJWT returns the JWSSignatureSuite registered for signatureType, or an error if none
+// is registered. Unlike GetSuite, this returns the concrete type so callers can reach SignJWS and
+// VerifyJWS, which aren't part of the SignatureSuite interface.
+func (f *SignatureSuiteFactory) GetSuiteForJWT(signatureType SignatureType) (*JWSSignatureSuite, error) {
+	var suite SignatureSuite
+	switch signatureType {
+	case JWTEdSignatureType:
+		suite = f.JWSEd25519
+	case JWTSecp256k1SignatureType:
+		suite = f.JWSSecp256k1
+	}
+	jws, ok := suite.(*JWSSignatureSuite)
+	if !ok {
+		return nil, fmt.Errorf("unsupported JWT signature type: %s", signatureType)
+	}
+	return jws, nil
+}
@@ -0,0 +1,49 @@
+package proof
+
+import "encoding/json"
+
+// Marshaler produces the bytes that get signed or verified for a Provable.
+type Marshaler interface {
+	Marshal(provable Provable) ([]byte, error)
+}
+
+// EmbeddedProofMarshaler marshals the Provable as-is, proof and all. It's used by suites whose
+// canonicalizer already covers every field of the document, including the proof itself once it has
+// been populated with everything but the signature value.
+type EmbeddedProofMarshaler struct{}
+
+func (EmbeddedProofMarshaler) Marshal(provable Provable) ([]byte, error) {
+	return json.Marshal(provable)
+}
+
+// WithoutProofMarshaler marshals the Provable with its SignatureValue stripped from the proof, so
+// the proof options (purpose, verification method, created, and its type) are covered by the
+// signature without the signature having to cover itself.
+//
+// OmitType reproduces our original (incorrect) behavior of dropping `type` from those proof
+// options entirely. Aries Framework Go found that other LD-proof implementations interpret the
+// spec as requiring `type` to stay, so the Ed25519 and WorkEd25519 suites in signaturesuite.go sign
+// with OmitType false and, via withLegacyTypeOmitted, keep an OmitType-true backup purely to keep
+// verifying signatures we produced before this fix. The other suites built on WithoutProofMarshaler
+// (the base64-digest credential-proof suites, the EcdsaSecp256k1 suites and Ed25519ph) also sign
+// with `type` retained but have no such backup, so they reject signatures made without `type`.
+type WithoutProofMarshaler struct {
+	OmitType bool
+}
+
+func (m WithoutProofMarshaler) Marshal(provable Provable) ([]byte, error) {
+	unsigned := provable.Copy()
+	existingProof := provable.GetProof()
+	if existingProof == nil {
+		unsigned.SetProof(nil)
+		return json.Marshal(unsigned)
+	}
+
+	options := *existingProof
+	options.SignatureValue = ""
+	if m.OmitType {
+		options.Type = ""
+	}
+	unsigned.SetProof(&options)
+	return json.Marshal(unsigned)
+}
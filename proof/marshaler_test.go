@@ -0,0 +1,137 @@
+package proof
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// stubProvable is a minimal Provable used to exercise Marshaler implementations without pulling in
+// the credential package.
+type stubProvable struct {
+	Claim string `json:"claim"`
+	Proof *Proof `json:"proof,omitempty"`
+}
+
+func (s *stubProvable) Copy() Provable {
+	cp := *s
+	return &cp
+}
+
+func (s *stubProvable) GetProof() *Proof {
+	return s.Proof
+}
+
+func (s *stubProvable) SetProof(p *Proof) {
+	s.Proof = p
+}
+
+func TestWithoutProofMarshaler_RetainsType(t *testing.T) {
+	doc := &stubProvable{
+		Claim: "hello",
+		Proof: &Proof{
+			Type:           Ed25519SignatureType,
+			SignatureValue: "should-not-be-signed-over",
+		},
+	}
+
+	bytes, err := (WithoutProofMarshaler{}).Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	var out stubProvable
+	if err := json.Unmarshal(bytes, &out); err != nil {
+		t.Fatalf("failed to unmarshal marshaled bytes: %v", err)
+	}
+	if out.Proof == nil {
+		t.Fatalf("expected proof options to be retained, got nil")
+	}
+	if out.Proof.Type != Ed25519SignatureType {
+		t.Errorf("expected type %q to be retained, got %q", Ed25519SignatureType, out.Proof.Type)
+	}
+	if out.Proof.SignatureValue != "" {
+		t.Errorf("expected signatureValue to be stripped, got %q", out.Proof.SignatureValue)
+	}
+}
+
+func TestWithoutProofMarshaler_LegacyOmitsType(t *testing.T) {
+	doc := &stubProvable{
+		Claim: "hello",
+		Proof: &Proof{
+			Type:           Ed25519SignatureType,
+			SignatureValue: "should-not-be-signed-over",
+		},
+	}
+
+	bytes, err := (WithoutProofMarshaler{OmitType: true}).Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	var out stubProvable
+	if err := json.Unmarshal(bytes, &out); err != nil {
+		t.Fatalf("failed to unmarshal marshaled bytes: %v", err)
+	}
+	if out.Proof == nil {
+		t.Fatalf("expected proof options to be present, got nil")
+	}
+	if out.Proof.Type != "" {
+		t.Errorf("expected legacy marshaler to omit type, got %q", out.Proof.Type)
+	}
+}
+
+// TestLegacyBackupSuiteVerifiesOldSignatures checks the backup chain that withLegacyTypeOmitted
+// builds, through which a signature produced against any of the three other proof option shapes
+// is verified now that the primary suite signs with a canonicalizer and type retained. It checks
+// the chain's structure only; it verifies no actual signature.
+func TestLegacyBackupSuiteVerifiesOldSignatures(t *testing.T) {
+	main := &LDSignatureSuite{
+		SignatureType:   Ed25519SignatureType,
+		KeyType:         Ed25519KeyType,
+		ProofFactory:    &proofFactoryV1{},
+		Marshaler:       &WithoutProofMarshaler{},
+		Canonicalizer:   &JCSCanonicalizer{},
+		OptionsAppender: &NonceAppender{},
+	}
+	withoutCanonicalizer := withAndWithoutCanonicalizer(main)
+	wrapped := withLegacyTypeOmitted(withoutCanonicalizer)
+
+	if wrapped.Main != withoutCanonicalizer.Main {
+		t.Fatalf("expected the primary (canonicalizer + type retained) suite to be untouched")
+	}
+
+	noCanonicalizerTypeRetained, ok := wrapped.Backup.(*compositeSignatureSuite)
+	if !ok {
+		t.Fatalf("expected wrapped.Backup to be a *compositeSignatureSuite, got %T", wrapped.Backup)
+	}
+	if noCanonicalizerTypeRetained.Main != withoutCanonicalizer.Backup {
+		t.Errorf("expected the no-canonicalizer, type-retained suite to be preserved as the next fallback")
+	}
+
+	legacyBranch, ok := noCanonicalizerTypeRetained.Backup.(*compositeSignatureSuite)
+	if !ok {
+		t.Fatalf("expected the next backup to be a *compositeSignatureSuite, got %T", noCanonicalizerTypeRetained.Backup)
+	}
+
+	withCanonicalizerTypeOmitted, ok := legacyBranch.Main.(*LDSignatureSuite)
+	if !ok {
+		t.Fatalf("expected the third branch to be a *LDSignatureSuite, got %T", legacyBranch.Main)
+	}
+	if _, ok := withCanonicalizerTypeOmitted.Marshaler.(*WithoutProofMarshaler); !ok || !withCanonicalizerTypeOmitted.Marshaler.(*WithoutProofMarshaler).OmitType {
+		t.Errorf("expected the third branch's Marshaler to omit type")
+	}
+	if withCanonicalizerTypeOmitted.Canonicalizer == nil {
+		t.Errorf("expected the third branch (oldest format predating only the type fix) to still canonicalize")
+	}
+
+	withoutCanonicalizerTypeOmitted, ok := legacyBranch.Backup.(*LDSignatureSuite)
+	if !ok {
+		t.Fatalf("expected the fourth (oldest) branch to be a *LDSignatureSuite, got %T", legacyBranch.Backup)
+	}
+	if _, ok := withoutCanonicalizerTypeOmitted.Marshaler.(*WithoutProofMarshaler); !ok || !withoutCanonicalizerTypeOmitted.Marshaler.(*WithoutProofMarshaler).OmitType {
+		t.Errorf("expected the fourth branch's Marshaler to omit type")
+	}
+	if withoutCanonicalizerTypeOmitted.Canonicalizer != nil {
+		t.Errorf("expected the fourth branch (the original pre-canonicalizer, pre-type-fix format) to have no canonicalizer")
+	}
+}
@@ -0,0 +1,108 @@
+package proof
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// FlexibleSecp256k1Verifier verifies EcdsaSecp256k1Signature2019 signatures that may arrive in
+// either of the two encodings seen in the wild: a raw 64-byte R||S concatenation (the form most
+// JWS/JOSE libraries and Bitcoin-style wallets produce) or a DER-encoded ASN.1 sequence (the form
+// Go's crypto/ecdsa and most PKI tooling produce).
+type FlexibleSecp256k1Verifier struct {
+	PublicKey *ecdsa.PublicKey
+}
+
+// NewFlexibleSecp256k1Verifier constructs a verifier from an uncompressed or compressed
+// secp256k1 public key.
+func NewFlexibleSecp256k1Verifier(pubKeyBytes []byte) (*FlexibleSecp256k1Verifier, error) {
+	key, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("invalid secp256k1 public key: %w", err)
+	}
+	return &FlexibleSecp256k1Verifier{PublicKey: key.ToECDSA()}, nil
+}
+
+// Verify checks sig against message, auto-detecting whether sig is a raw R||S pair or a
+// DER-encoded ASN.1 sequence.
+func (v *FlexibleSecp256k1Verifier) Verify(message, sig []byte) error {
+	r, s, err := decodeSecp256k1Signature(sig)
+	if err != nil {
+		return err
+	}
+
+	curveOrder := v.PublicKey.Curve.Params().N
+	if r.Sign() <= 0 || r.Cmp(curveOrder) >= 0 || s.Sign() <= 0 || s.Cmp(curveOrder) >= 0 {
+		return fmt.Errorf("invalid secp256k1 signature: R or S out of range")
+	}
+
+	digest := sha256.Sum256(message)
+	if !ecdsa.Verify(v.PublicKey, digest[:], r, s) {
+		return fmt.Errorf("secp256k1 signature verification failed")
+	}
+	return nil
+}
+
+// decodeSecp256k1Signature accepts either a raw 64-byte R||S signature or a DER-encoded ASN.1
+// ECDSA-Sig-Value sequence, and returns the decoded R, S values.
+func decodeSecp256k1Signature(sig []byte) (r, s *big.Int, err error) {
+	if len(sig) == 64 {
+		r = new(big.Int).SetBytes(sig[:32])
+		s = new(big.Int).SetBytes(sig[32:])
+		return r, s, nil
+	}
+
+	var parsed struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(sig, &parsed); err != nil {
+		return nil, nil, fmt.Errorf("signature is neither a raw 64-byte R||S pair nor valid DER: %w", err)
+	}
+	return parsed.R, parsed.S, nil
+}
+
+// PublicKeyJWK is the subset of RFC 7517 JSON Web Key fields needed to resolve a key published in
+// a DID document's verificationMethod as a JWK, e.g. `kty=EC, crv=secp256k1`.
+type PublicKeyJWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// DecodeSecp256k1JWK parses a JWK-encoded secp256k1 public key (kty=EC, crv=secp256k1) into an
+// ecdsa.PublicKey.
+func DecodeSecp256k1JWK(jwk PublicKeyJWK) (*ecdsa.PublicKey, error) {
+	if jwk.Kty != "EC" || jwk.Crv != "secp256k1" {
+		return nil, fmt.Errorf("not a secp256k1 JWK: kty=%s crv=%s", jwk.Kty, jwk.Crv)
+	}
+
+	x, err := base64URLBigInt(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWK x coordinate: %w", err)
+	}
+	y, err := base64URLBigInt(jwk.Y)
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWK y coordinate: %w", err)
+	}
+
+	curve := btcec.S256()
+	if !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("JWK point is not on the secp256k1 curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func base64URLBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
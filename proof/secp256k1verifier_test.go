@@ -0,0 +1,138 @@
+package proof
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+func generateSecp256k1Key(t *testing.T) (*btcec.PrivateKey, *FlexibleSecp256k1Verifier) {
+	t.Helper()
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("failed to generate secp256k1 key: %v", err)
+	}
+	verifier, err := NewFlexibleSecp256k1Verifier(priv.PubKey().SerializeUncompressed())
+	if err != nil {
+		t.Fatalf("NewFlexibleSecp256k1Verifier returned error: %v", err)
+	}
+	return priv, verifier
+}
+
+func rawSign(t *testing.T, priv *btcec.PrivateKey, message []byte) []byte {
+	t.Helper()
+	digest := sha256.Sum256(message)
+	r, s, err := ecdsa.Sign(rand.Reader, priv.ToECDSA(), digest[:])
+	if err != nil {
+		t.Fatalf("ecdsa.Sign returned error: %v", err)
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return sig
+}
+
+func derSign(t *testing.T, priv *btcec.PrivateKey, message []byte) []byte {
+	t.Helper()
+	digest := sha256.Sum256(message)
+	r, s, err := ecdsa.Sign(rand.Reader, priv.ToECDSA(), digest[:])
+	if err != nil {
+		t.Fatalf("ecdsa.Sign returned error: %v", err)
+	}
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		t.Fatalf("asn1.Marshal returned error: %v", err)
+	}
+	return der
+}
+
+func TestFlexibleSecp256k1Verifier_VerifiesRawSignature(t *testing.T) {
+	priv, verifier := generateSecp256k1Key(t)
+	message := []byte("hello world")
+	sig := rawSign(t, priv, message)
+
+	if err := verifier.Verify(message, sig); err != nil {
+		t.Errorf("Verify rejected a valid raw R||S signature: %v", err)
+	}
+}
+
+func TestFlexibleSecp256k1Verifier_VerifiesDERSignature(t *testing.T) {
+	priv, verifier := generateSecp256k1Key(t)
+	message := []byte("hello world")
+	sig := derSign(t, priv, message)
+
+	if err := verifier.Verify(message, sig); err != nil {
+		t.Errorf("Verify rejected a valid DER-encoded signature: %v", err)
+	}
+}
+
+func TestFlexibleSecp256k1Verifier_RejectsTamperedMessage(t *testing.T) {
+	priv, verifier := generateSecp256k1Key(t)
+	sig := rawSign(t, priv, []byte("hello world"))
+
+	if err := verifier.Verify([]byte("goodbye world"), sig); err == nil {
+		t.Errorf("expected Verify to reject a signature over a different message")
+	}
+}
+
+func TestFlexibleSecp256k1Verifier_RejectsMalformedSignature(t *testing.T) {
+	_, verifier := generateSecp256k1Key(t)
+
+	if err := verifier.Verify([]byte("hello world"), []byte("not a signature")); err == nil {
+		t.Errorf("expected Verify to reject a signature that is neither raw R||S nor DER")
+	}
+}
+
+func TestFlexibleSecp256k1Verifier_RejectsOutOfRangeRawSignature(t *testing.T) {
+	_, verifier := generateSecp256k1Key(t)
+
+	zeroSig := make([]byte, 64)
+	if err := verifier.Verify([]byte("hello world"), zeroSig); err == nil {
+		t.Errorf("expected Verify to reject a signature with R=S=0")
+	}
+}
+
+func TestDecodeSecp256k1JWK_RoundTrip(t *testing.T) {
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("failed to generate secp256k1 key: %v", err)
+	}
+	pub := priv.PubKey()
+
+	jwk := PublicKeyJWK{
+		Kty: "EC",
+		Crv: "secp256k1",
+		X:   base64.RawURLEncoding.EncodeToString(pub.X.Bytes()),
+		Y:   base64.RawURLEncoding.EncodeToString(pub.Y.Bytes()),
+	}
+
+	decoded, err := DecodeSecp256k1JWK(jwk)
+	if err != nil {
+		t.Fatalf("DecodeSecp256k1JWK returned error: %v", err)
+	}
+	if decoded.X.Cmp(pub.X) != 0 || decoded.Y.Cmp(pub.Y) != 0 {
+		t.Errorf("decoded public key does not match the original: got (%v, %v)", decoded.X, decoded.Y)
+	}
+
+	message := []byte("hello world")
+	sig := rawSign(t, priv, message)
+	verifier := &FlexibleSecp256k1Verifier{PublicKey: decoded}
+	if err := verifier.Verify(message, sig); err != nil {
+		t.Errorf("Verify rejected a valid signature against a JWK-decoded key: %v", err)
+	}
+}
+
+func TestDecodeSecp256k1JWK_RejectsWrongKeyType(t *testing.T) {
+	if _, err := DecodeSecp256k1JWK(PublicKeyJWK{Kty: "RSA", Crv: "secp256k1"}); err == nil {
+		t.Errorf("expected DecodeSecp256k1JWK to reject a non-EC kty")
+	}
+	if _, err := DecodeSecp256k1JWK(PublicKeyJWK{Kty: "EC", Crv: "P-256"}); err == nil {
+		t.Errorf("expected DecodeSecp256k1JWK to reject a non-secp256k1 curve")
+	}
+}
@@ -8,7 +8,7 @@ import (
 // This model is based on the W3C Linked-Data Proofs, see https://w3c-ccg.github.io/ld-proofs.
 type SignatureSuite interface {
 	Type() SignatureType
-	Sign(provable Provable, signer Signer) error
+	Sign(provable Provable, signer Signer, opts *ProofOptions) error
 	Verify(provable Provable, verifier Verifier) error
 }
 
@@ -38,6 +38,52 @@ func withB64Digest(suite *LDSignatureSuite) *LDSignatureSuite {
 	return &updated
 }
 
+// withLegacyTypeOmitted nests two further backups behind suite for proofs that were signed before
+// WithoutProofMarshaler started retaining the `type` property in the canonicalized proof options
+// (Aries Framework Go found other LD-proof implementations expect it to stay, since no RFC requires
+// dropping it). Canonicalizers predate that fix (see withAndWithoutCanonicalizer), and verification
+// tries four combinations, the current one first and the original format last:
+//
+//  1. canonicalizer present, type retained   (suite.Main, current)
+//  2. canonicalizer absent,  type retained   (suite.Backup, from withAndWithoutCanonicalizer)
+//  3. canonicalizer present, type omitted
+//  4. canonicalizer absent,  type omitted    (the original format, predating both fixes)
+//
+// suite is expected to already be the output of withAndWithoutCanonicalizer, so suite.Backup covers
+// combination 2; this wraps in the remaining two, each reusing suite.Main's configuration with
+// Marshaler swapped for a WithoutProofMarshaler that omits `type`.
+//
+// No signature captured from a third-party LD-proofs library is tested against this. marshaler_test.go
+// checks that WithoutProofMarshaler keeps or omits `type` as configured
+// (TestWithoutProofMarshaler_RetainsType/_LegacyOmitsType) and the structure of the chain built here
+// (TestLegacyBackupSuiteVerifiesOldSignatures).
+func withLegacyTypeOmitted(suite *compositeSignatureSuite) *compositeSignatureSuite {
+	mainLD, ok := suite.Main.(*LDSignatureSuite)
+	if !ok {
+		return suite
+	}
+	if _, ok := mainLD.Marshaler.(*WithoutProofMarshaler); !ok {
+		return suite
+	}
+
+	legacyWithCanonicalizer := *mainLD
+	legacyWithCanonicalizer.Marshaler = &WithoutProofMarshaler{OmitType: true}
+
+	legacyWithoutCanonicalizer := legacyWithCanonicalizer
+	legacyWithoutCanonicalizer.Canonicalizer = nil
+
+	return &compositeSignatureSuite{
+		Main: suite.Main,
+		Backup: &compositeSignatureSuite{
+			Main: suite.Backup,
+			Backup: &compositeSignatureSuite{
+				Main:   &legacyWithCanonicalizer,
+				Backup: &legacyWithoutCanonicalizer,
+			},
+		},
+	}
+}
+
 // compositeSignatureSuite wraps two suites in order to support (unintended) variable
 // canonicalization of some signature schemes. We designate a main suite and a backup.
 // The signature generation always uses the primary suite. On verification, if the main suite fails,
@@ -51,8 +97,8 @@ func (s *compositeSignatureSuite) Type() SignatureType {
 	return s.Main.Type()
 }
 
-func (s *compositeSignatureSuite) Sign(provable Provable, signer Signer) error {
-	return s.Main.Sign(provable, signer)
+func (s *compositeSignatureSuite) Sign(provable Provable, signer Signer, opts *ProofOptions) error {
+	return s.Main.Sign(provable, signer, opts)
 }
 
 func (s *compositeSignatureSuite) Verify(provable Provable, verifier Verifier) error {
@@ -75,6 +121,16 @@ type SignatureSuiteFactory struct {
 	Ed25519v2 SignatureSuite
 	// EcdsaSecp256k1 Signature suite with v1 Proofs
 	Secp256k1 SignatureSuite
+	// EcdsaSecp256k1 Signature suite with v2 Proofs
+	Secp256k1v2 SignatureSuite
+	// JWSEd25519 Signature suite used for JWT-serialized VCs/VPs
+	JWSEd25519 SignatureSuite
+	// JWSSecp256k1 Signature suite used for JWT-serialized VCs/VPs
+	JWSSecp256k1 SignatureSuite
+	// BbsBls2020 Signature suite for selective-disclosure BBS+ credential proofs
+	BbsBls2020 SignatureSuite
+	// Ed25519ph Signature suite with v2 Proofs
+	Ed25519ph SignatureSuite
 }
 
 // GetSuiteForProof returns the correct type of SignatureSuite to use to verify the given Proof.
@@ -117,6 +173,14 @@ func (f *SignatureSuiteFactory) getSuiteV2(signatureType SignatureType) Signatur
 		return f.WorkEd25519v2
 	case Ed25519SignatureType:
 		return f.Ed25519v2
+	case JWTEdSignatureType:
+		return f.JWSEd25519
+	case JWTSecp256k1SignatureType:
+		return f.JWSSecp256k1
+	case EcdsaSecp256k1SignatureType:
+		return f.Secp256k1v2
+	case Ed25519phSignatureType:
+		return f.Ed25519ph
 	}
 	return nil
 }
@@ -137,12 +201,30 @@ func (f *SignatureSuiteFactory) GetSuiteForCredentialProof(proof *Proof) (suite
 	return
 }
 
+// GetSuiteForCredentials returns the correct SignatureSuite to use for signing or verifying a
+// Verifiable Credential of a particular SignatureType and Proof model version. Unlike
+// GetSuiteForCredentialProof, this is used by Builder before a Proof exists to be inspected.
+func (f *SignatureSuiteFactory) GetSuiteForCredentials(signatureType SignatureType, modelVersion ModelVersion) (suite SignatureSuite, err error) {
+	switch modelVersion {
+	case V1:
+		suite = f.getSuiteV1Cred(signatureType)
+	case V2:
+		suite = f.getSuiteV2Cred(signatureType)
+	}
+	if suite == nil {
+		err = fmt.Errorf("unsupported signature type")
+	}
+	return
+}
+
 func (f *SignatureSuiteFactory) getSuiteV1Cred(signatureType SignatureType) SignatureSuite {
 	switch signatureType {
 	case Ed25519SignatureType:
 		return ed25519SignatureSuiteV1B64
 	case WorkEdSignatureType:
 		return workSignatureSuiteV1B64
+	case EcdsaSecp256k1SignatureType:
+		return secp256K1SignatureSuiteV1B64
 	default:
 		return nil
 	}
@@ -156,6 +238,10 @@ func (f *SignatureSuiteFactory) getSuiteV2Cred(signatureType SignatureType) Sign
 		return workSignatureSuiteV2B64
 	case JCSEdSignatureType:
 		return jcsEd25519SignatureSuite
+	case BbsBlsSignature2020:
+		return f.BbsBls2020
+	case EcdsaSecp256k1SignatureType:
+		return secp256K1SignatureSuiteV2B64
 	default:
 		return nil
 	}
@@ -169,6 +255,11 @@ func SignatureSuites() *SignatureSuiteFactory {
 		Ed25519:       ed25519SignatureSuiteV1,
 		Ed25519v2:     ed25519SignatureSuiteV2,
 		Secp256k1:     secp256K1SignatureSuite,
+		JWSEd25519:    jwsEd25519SignatureSuite,
+		JWSSecp256k1:  jwsSecp256k1SignatureSuite,
+		BbsBls2020:    bbsSignatureSuite,
+		Secp256k1v2:   secp256K1SignatureSuiteV2,
+		Ed25519ph:     ed25519phSignatureSuite,
 	}
 }
 
@@ -183,7 +274,7 @@ var (
 	}
 
 	// General WorkEd25519 signatures with "creator" field.
-	workSignatureSuiteV1 = withAndWithoutCanonicalizer(
+	workSignatureSuiteV1 = withLegacyTypeOmitted(withAndWithoutCanonicalizer(
 		&LDSignatureSuite{
 			SignatureType:   WorkEdSignatureType,
 			KeyType:         Ed25519KeyType,
@@ -191,11 +282,11 @@ var (
 			Marshaler:       &WithoutProofMarshaler{},
 			Canonicalizer:   &JCSCanonicalizer{},
 			OptionsAppender: &NonceAppender{},
-		})
+		}))
 
 	// General WorkEd25519 signatures with "verificationMethod" field.
-	workSignatureSuiteV2 = withAndWithoutCanonicalizer(
-		withV2Proofs(workSignatureSuiteV1.Main.(*LDSignatureSuite)))
+	workSignatureSuiteV2 = withLegacyTypeOmitted(withAndWithoutCanonicalizer(
+		withV2Proofs(workSignatureSuiteV1.Main.(*LDSignatureSuite))))
 
 	// WorkEd25519 signatures with "creator" field on credential proofs.
 	workSignatureSuiteV1B64 = withAndWithoutCanonicalizer(
@@ -206,7 +297,7 @@ var (
 		withV2Proofs(withB64Digest(workSignatureSuiteV1.Main.(*LDSignatureSuite))))
 
 	// Ed25519 signatures with "creator" field.
-	ed25519SignatureSuiteV1 = withAndWithoutCanonicalizer(
+	ed25519SignatureSuiteV1 = withLegacyTypeOmitted(withAndWithoutCanonicalizer(
 		&LDSignatureSuite{
 			SignatureType:   Ed25519SignatureType,
 			KeyType:         Ed25519KeyType,
@@ -214,11 +305,11 @@ var (
 			Marshaler:       &WithoutProofMarshaler{},
 			Canonicalizer:   &JCSCanonicalizer{},
 			OptionsAppender: &NonceAppender{},
-		})
+		}))
 
 	// Ed25519 signatures with "verificationMethod" field.
-	ed25519SignatureSuiteV2 = withAndWithoutCanonicalizer(
-		withV2Proofs(ed25519SignatureSuiteV1.Main.(*LDSignatureSuite)))
+	ed25519SignatureSuiteV2 = withLegacyTypeOmitted(withAndWithoutCanonicalizer(
+		withV2Proofs(ed25519SignatureSuiteV1.Main.(*LDSignatureSuite))))
 
 	// Ed25519 signatures with "creator" field on credential proofs.
 	ed25519SignatureSuiteV1B64 = withAndWithoutCanonicalizer(
@@ -237,4 +328,27 @@ var (
 		Canonicalizer:   &JCSCanonicalizer{},
 		OptionsAppender: &NonceAppender{},
 	}
-)
\ No newline at end of file
+
+	// EcdsaSecp256k1Signature2019 signatures with "verificationMethod" field.
+	secp256K1SignatureSuiteV2 = withV2Proofs(secp256K1SignatureSuite)
+
+	// EcdsaSecp256k1 signatures with "creator" field on credential proofs.
+	secp256K1SignatureSuiteV1B64 = withB64Digest(secp256K1SignatureSuite)
+
+	// EcdsaSecp256k1Signature2019 signatures with "verificationMethod" field on credential proofs.
+	secp256K1SignatureSuiteV2B64 = withV2Proofs(withB64Digest(secp256K1SignatureSuite))
+
+	// JWS signatures for JWT-serialized VCs/VPs, keyed by an Ed25519 verification method.
+	jwsEd25519SignatureSuite = &JWSSignatureSuite{
+		SignatureType: JWTEdSignatureType,
+		KeyType:       Ed25519KeyType,
+		Alg:           "EdDSA",
+	}
+
+	// JWS signatures for JWT-serialized VCs/VPs, keyed by a secp256k1 verification method.
+	jwsSecp256k1SignatureSuite = &JWSSignatureSuite{
+		SignatureType: JWTSecp256k1SignatureType,
+		KeyType:       EcdsaSecp256k1KeyType,
+		Alg:           "ES256K",
+	}
+)